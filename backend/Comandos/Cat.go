@@ -1,13 +1,10 @@
 package Comandos
 
 import (
-	"encoding/binary"
 	"fmt"
-	"os"
 	"strings"
-	"unsafe"
 
-	"godisk-backend/Structs"
+	"godisk-backend/FS"
 	"godisk-backend/Utils"
 )
 
@@ -106,238 +103,36 @@ func leerArchivoReal(rutaArchivo string, idParticion string) string {
 
 	fmt.Printf("🔧 DEBUG: Buscando archivo '%s' en partición %s\n", rutaArchivo, idFinal)
 
-	// 1. Obtener la partición montada
-	var pathDisco string
-	particion := GetMount("CAT", idFinal, &pathDisco)
-	if particion == nil {
-		fmt.Printf("❌ CAT: Partición %s no está montada\n", idFinal)
+	// 1. Abrir el sistema de archivos de la partición montada
+	v, err := abrirVolumen("CAT", idFinal)
+	if err != nil {
+		fmt.Printf("❌ CAT: %v\n", err)
 		return ""
 	}
+	defer v.Cerrar()
 
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
+	fmt.Printf("🔧 DEBUG: SuperBloque leído - FS: %d, Inodos: %d\n",
+		v.Super.S_filesystem_type, v.Super.S_inodes_count)
 
-	// 2. Abrir archivo del disco
-	file, err := os.Open(pathDisco)
+	// 2. Resolver la ruta y leer el archivo
+	_, inodo, err := v.ResolvePath(rutaArchivo)
 	if err != nil {
-		fmt.Printf("❌ CAT: Error al abrir disco: %v\n", err)
+		fmt.Printf("❌ CAT: %v\n", err)
 		return ""
 	}
-	defer file.Close()
-
-	// 3. Leer superbloque
-	file.Seek(particion.Part_start, 0)
-	var superbloque Structs.SuperBloque
-	if err := binary.Read(file, binary.BigEndian, &superbloque); err != nil {
-		fmt.Printf("❌ CAT: Error al leer superbloque: %v\n", err)
+	if inodo.I_type != FS.TipoArchivo {
+		fmt.Printf("❌ CAT: '%s' no es un archivo (tipo: %d)\n", rutaArchivo, inodo.I_type)
 		return ""
 	}
 
-	fmt.Printf("🔧 DEBUG: SuperBloque leído - FS: %d, Inodos: %d\n",
-		superbloque.S_filesystem_type, superbloque.S_inodes_count)
-
-	// 4. Buscar el archivo en el sistema de archivos
-	contenido := buscarArchivoEnSistema(file, superbloque, rutaArchivo)
-
-	return contenido
-}
-
-// buscarArchivoEnSistema busca un archivo en el sistema EXT2 y retorna su contenido
-func buscarArchivoEnSistema(file *os.File, sb Structs.SuperBloque, rutaArchivo string) string {
-	// Separar la ruta en componentes
-	componentes := strings.Split(strings.Trim(rutaArchivo, "/"), "/")
-	if len(componentes) == 1 && componentes[0] == "" {
-		componentes = []string{} // Ruta raíz
-	}
-
-	fmt.Printf("🔧 DEBUG: Buscando componentes: %v\n", componentes)
-
-	// CASO ESPECIAL: Archivo directamente en la raíz (como "users.txt")
-	if len(componentes) == 1 && componentes[0] != "" {
-		fmt.Printf("🔧 DEBUG: Buscando archivo '%s' en directorio raíz\n", componentes[0])
-
-		// Leer inodo del directorio raíz (inodo 0)
-		inodoRaiz, err := leerInodo(file, sb, 0)
-		if err != nil {
-			fmt.Printf("❌ CAT: Error al leer inodo raíz: %v\n", err)
-			return ""
-		}
-
-		// Buscar el archivo en el directorio raíz
-		inodoArchivo := buscarEnDirectorio(file, sb, inodoRaiz, componentes[0])
-		if inodoArchivo == -1 {
-			fmt.Printf("❌ CAT: No se encontró '%s' en el directorio raíz\n", componentes[0])
-			return ""
-		}
-
-		// Leer el inodo del archivo
-		inodo, err := leerInodo(file, sb, inodoArchivo)
-		if err != nil {
-			fmt.Printf("❌ CAT: Error al leer inodo del archivo: %v\n", err)
-			return ""
-		}
-
-		// Verificar que es un archivo
-		if inodo.I_type != 1 {
-			fmt.Printf("❌ CAT: '%s' no es un archivo (tipo: %d)\n", componentes[0], inodo.I_type)
-			return ""
-		}
-
-		// Leer y retornar el contenido
-		return leerContenidoArchivo(file, sb, inodo)
-	}
-
-	// CASO GENERAL: Navegación por directorios para rutas más complejas
-	inodoActual := int64(0)
-
-	// Navegar por cada componente de la ruta
-	for i, componente := range componentes {
-		fmt.Printf("🔧 DEBUG: Procesando componente '%s' (nivel %d)\n", componente, i)
-
-		// Leer el inodo actual
-		inodo, err := leerInodo(file, sb, inodoActual)
-		if err != nil {
-			fmt.Printf("❌ CAT: Error al leer inodo %d: %v\n", inodoActual, err)
-			return ""
-		}
-
-		// Si es el último componente y esperamos un archivo
-		if i == len(componentes)-1 {
-			if inodo.I_type == 1 { // Es archivo
-				fmt.Printf("✅ DEBUG: Archivo encontrado en inodo %d\n", inodoActual)
-				return leerContenidoArchivo(file, sb, inodo)
-			} else {
-				fmt.Printf("❌ CAT: '%s' es un directorio, no un archivo\n", componente)
-				return ""
-			}
-		}
-
-		// Si no es el último componente, debe ser un directorio
-		if inodo.I_type != 0 {
-			fmt.Printf("❌ CAT: '%s' no es un directorio\n", componente)
-			return ""
-		}
-
-		// Buscar el siguiente componente en el directorio actual
-		siguienteInodo := buscarEnDirectorio(file, sb, inodo, componente)
-		if siguienteInodo == -1 {
-			fmt.Printf("❌ CAT: No se encontró '%s' en el directorio\n", componente)
-			return ""
-		}
-
-		inodoActual = siguienteInodo
-	}
-
-	// Si llegamos aquí, la ruta era solo "/" (directorio raíz)
-	fmt.Printf("❌ CAT: No se puede hacer CAT de un directorio\n")
-	return ""
-}
-
-// leerInodo lee un inodo específico del sistema de archivos
-func leerInodo(file *os.File, sb Structs.SuperBloque, numeroInodo int64) (Structs.Inodos, error) {
-	var inodo Structs.Inodos
-
-	// Calcular posición del inodo
-	inodoSize := int64(unsafe.Sizeof(Structs.Inodos{}))
-	posicion := sb.S_inode_start + (numeroInodo * inodoSize)
-
-	fmt.Printf("🔧 DEBUG: Leyendo inodo %d en posición %d\n", numeroInodo, posicion)
-
-	// Leer el inodo
-	file.Seek(posicion, 0)
-	err := binary.Read(file, binary.BigEndian, &inodo)
-
-	if err == nil {
-		fmt.Printf("🔧 DEBUG: Inodo %d - Tipo: %d, Tamaño: %d, Bloque[0]: %d\n",
-			numeroInodo, inodo.I_type, inodo.I_size, inodo.I_block[0])
-	}
-
-	return inodo, err
-}
-
-// buscarEnDirectorio busca una entrada en un directorio y retorna el número de inodo
-func buscarEnDirectorio(file *os.File, sb Structs.SuperBloque, inodoDir Structs.Inodos, nombreBuscado string) int64 {
-	// Leer el bloque del directorio
-	bloqueSize := int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	posicionBloque := sb.S_block_start + (inodoDir.I_block[0] * bloqueSize)
-
-	fmt.Printf("🔧 DEBUG: Buscando '%s' en directorio, bloque en posición %d\n", nombreBuscado, posicionBloque)
-
-	file.Seek(posicionBloque, 0)
-	var bloque Structs.BloquesCarpetas
-	if err := binary.Read(file, binary.BigEndian, &bloque); err != nil {
-		fmt.Printf("❌ CAT: Error al leer bloque de directorio: %v\n", err)
-		return -1
-	}
-
-	// Buscar en las entradas del directorio
-	for i, entrada := range bloque.B_content {
-		if entrada.B_inodo == -1 { // Entrada vacía
-			continue
-		}
-
-		// Convertir nombre de la entrada a string
-		nombreEntrada := ""
-		for _, b := range entrada.B_name {
-			if b != 0 {
-				nombreEntrada += string(b)
-			} else {
-				break
-			}
-		}
-
-		fmt.Printf("🔧 DEBUG: Entrada[%d]: '%s' -> inodo %d\n", i, nombreEntrada, entrada.B_inodo)
-
-		if nombreEntrada == nombreBuscado {
-			fmt.Printf("✅ DEBUG: Encontrado '%s' -> inodo %d\n", nombreBuscado, entrada.B_inodo)
-			return entrada.B_inodo
-		}
-	}
-
-	return -1 // No encontrado
-}
-
-func leerContenidoArchivo(file *os.File, sb Structs.SuperBloque, inodo Structs.Inodos) string {
-	if inodo.I_type != 1 {
-		return "" // No es un archivo
-	}
-
-	fmt.Printf("🔧 DEBUG: Iniciando lectura de contenido de archivo\n")
-	fmt.Printf("🔧 DEBUG: Inodo - Tipo: %d, Tamaño: %d bytes, Bloque[0]: %d\n",
-		inodo.I_type, inodo.I_size, inodo.I_block[0])
-
-	mitadBA := sb.S_block_start + int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	TamBA := int64(unsafe.Sizeof(Structs.BloquesArchivos{}))
-	var contenido strings.Builder
-
-	for bloque := 0; bloque < 16; bloque++ {
-		if inodo.I_block[bloque] == -1 {
-			break
-		}
-
-		PunteroBA := mitadBA + (int64(inodo.I_block[bloque]-1) * TamBA)
-
-		fmt.Printf("🔧 DEBUG: Leyendo bloque %d en posición %d (Nº%d)\n",
-			inodo.I_block[bloque], PunteroBA, bloque)
-
-		// Leer el bloque
-		file.Seek(PunteroBA, 0)
-		var fb Structs.BloquesArchivos
-		err := binary.Read(file, binary.BigEndian, &fb)
-		if err != nil {
-			fmt.Printf("❌ CAT: Error leyendo bloque: %v\n", err)
-			continue
-		}
-
-		for i := 0; i < len(fb.B_content); i++ {
-			if fb.B_content[i] != 0 {
-				contenido.WriteByte(fb.B_content[i])
-			}
-		}
+	contenido, err := v.LeerArchivo(inodo)
+	if err != nil {
+		fmt.Printf("❌ CAT: Error al leer contenido: %v\n", err)
+		return ""
 	}
 
-	resultado := contenido.String()
-	fmt.Printf("✅ DEBUG: Contenido leído (%d bytes): %q\n", len(resultado), resultado)
-	return resultado
+	fmt.Printf("✅ DEBUG: Contenido leído (%d bytes)\n", len(contenido))
+	return contenido
 }
 
 // obtenerParticionDeSesion obtiene el ID de partición de la sesión activa
@@ -0,0 +1,255 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Structs"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosCOPY valida los parámetros del comando COPY
+func ValidarDatosCOPY(tokens []string) string {
+	path, destino, err := parametrosOrigenDestino("COPY", tokens)
+	if err != "" {
+		return err
+	}
+	return copiar(path, destino)
+}
+
+// parametrosOrigenDestino lee -path y -destino, comunes a COPY y MOVE, y
+// verifica que haya sesión. Retorna el mensaje de error si algo falta.
+func parametrosOrigenDestino(comando string, tokens []string) (string, string, string) {
+	path := ""
+	destino := ""
+	for _, token := range tokens {
+		tk := strings.SplitN(token, "=", 2)
+		if len(tk) != 2 {
+			continue
+		}
+		switch strings.ToLower(tk[0]) {
+		case "path":
+			path = strings.ReplaceAll(tk[1], "\"", "")
+		case "destino":
+			destino = strings.ReplaceAll(tk[1], "\"", "")
+		default:
+			return "", "", Utils.Error(comando, "Parámetro no reconocido: "+tk[0])
+		}
+	}
+
+	if path == "" || destino == "" {
+		return "", "", Utils.Error(comando, "Los parámetros -path y -destino son obligatorios")
+	}
+	if !EstaLogueado() {
+		return "", "", Utils.Error(comando, "Debe iniciar sesión para ejecutar este comando")
+	}
+	return path, destino, ""
+}
+
+// copiar duplica el archivo o carpeta path dentro de la carpeta destino con
+// inodos y bloques nuevos. Los elementos sin permiso de lectura se omiten.
+func copiar(path, destino string) string {
+	fmt.Printf("🔧 DEBUG: COPY path='%s' destino='%s'\n", path, destino)
+
+	sesion := ObtenerSesionActiva()
+	v, err := abrirVolumen("COPY", sesion.Id)
+	if err != nil {
+		return Utils.Error("COPY", err.Error())
+	}
+	defer v.Cerrar()
+
+	copia, err := contextoSesion(v).copiarRuta(path, destino)
+	if err != nil {
+		return Utils.Error("COPY", err.Error())
+	}
+
+	mensaje := fmt.Sprintf("'%s' copiado en '%s' (%d elementos)", path, destino, copia.copiados)
+	if len(copia.omitidos) > 0 {
+		mensaje += "\n   ⚠️ Omitidos por falta de permiso de lectura: " + strings.Join(copia.omitidos, ", ")
+	}
+	return Utils.Mensaje("COPY", mensaje)
+}
+
+// copiarRuta verifica y registra la copia de path en destino y luego copia
+// el subárbol con el usuario del contexto como propietario
+func (c *contextoFS) copiarRuta(path, destino string) (*copiaFS, error) {
+	componentes := FS.SepararRuta(path)
+	if len(componentes) == 0 {
+		return nil, fmt.Errorf("no se puede copiar la carpeta raíz")
+	}
+	nombre := componentes[len(componentes)-1]
+
+	nOrigen, origen, err := c.v.ResolvePath(path)
+	if err != nil {
+		return nil, err
+	}
+	nDestino, dirDestino, err := validarDestino(c.v, destino, nOrigen, origen, nombre)
+	if err != nil {
+		return nil, err
+	}
+	if !c.puede(dirDestino, permisoEscritura) {
+		return nil, fmt.Errorf("no tiene permiso de escritura sobre %s", destino)
+	}
+	if !c.puede(origen, permisoLectura) {
+		return nil, fmt.Errorf("no tiene permiso de lectura sobre %s", path)
+	}
+
+	// Registrar la operación en el journal antes de modificar el disco
+	if err := c.registrar("copy", path, destino); err != nil {
+		return nil, err
+	}
+
+	copia := &copiaFS{contextoFS: c, vistos: map[int64]bool{}}
+	if err := copia.copiarElemento(nOrigen, origen, nDestino, nombre, "/"+strings.Join(componentes, "/")); err != nil {
+		// No dejar en el destino un subárbol a medio copiar
+		if errDeshacer := copia.deshacer(nDestino, nombre); errDeshacer != nil {
+			return nil, fmt.Errorf("%v; no se pudo deshacer la copia parcial: %v", err, errDeshacer)
+		}
+		return nil, err
+	}
+	return copia, nil
+}
+
+// validarDestino verifica que destino sea una carpeta sin una entrada nombre
+// y que no esté dentro del origen, y retorna su número e inodo
+func validarDestino(v *FS.Volumen, destino string, nOrigen int64, origen Structs.Inodos, nombre string) (int64, Structs.Inodos, error) {
+	nDestino, dirDestino, err := v.ResolvePath(destino)
+	if err != nil {
+		return -1, dirDestino, err
+	}
+	if dirDestino.I_type != FS.TipoCarpeta {
+		return -1, dirDestino, fmt.Errorf("el destino %s no es una carpeta", destino)
+	}
+	if existente, err := v.BuscarEnDirectorio(dirDestino, nombre); err != nil {
+		return -1, dirDestino, err
+	} else if existente != -1 {
+		return -1, dirDestino, fmt.Errorf("ya existe '%s' en %s", nombre, destino)
+	}
+	if origen.I_type == FS.TipoCarpeta {
+		dentro, err := esDescendiente(v, nDestino, nOrigen)
+		if err != nil {
+			return -1, dirDestino, err
+		}
+		if dentro {
+			return -1, dirDestino, fmt.Errorf("el destino %s está dentro de la carpeta de origen", destino)
+		}
+	}
+	return nDestino, dirDestino, nil
+}
+
+// esDescendiente indica si la carpeta n es la carpeta ancestro o está dentro
+// de ella, subiendo por las entradas ".." hasta la raíz
+func esDescendiente(v *FS.Volumen, n, ancestro int64) (bool, error) {
+	for pasos := int64(0); pasos <= v.Super.S_inodes_count; pasos++ {
+		if n == ancestro {
+			return true, nil
+		}
+		if n == 0 {
+			return false, nil
+		}
+		inodo, err := v.ReadInode(n)
+		if err != nil {
+			return false, err
+		}
+		if n, err = v.BuscarEnDirectorio(inodo, ".."); err != nil {
+			return false, err
+		}
+		if n == -1 {
+			return false, fmt.Errorf("la carpeta no tiene entrada '..'")
+		}
+	}
+	return false, fmt.Errorf("ciclo en las entradas '..'")
+}
+
+// copiaFS acumula el estado de una copia recursiva
+type copiaFS struct {
+	*contextoFS
+	vistos   map[int64]bool // inodos de origen ya copiados
+	creados  []int64        // inodos nuevos, padres antes que hijos
+	copiados int
+	omitidos []string
+}
+
+// copiarElemento crea en nDestino la copia nombre del inodo n. Las carpetas
+// nuevas reciben "." y ".." propios y se copian sus entradas.
+func (c *copiaFS) copiarElemento(n int64, inodo Structs.Inodos, nDestino int64, nombre, ruta string) error {
+	if c.vistos[n] {
+		return nil
+	}
+	c.vistos[n] = true
+	if !c.puede(inodo, permisoLectura) {
+		c.omitidos = append(c.omitidos, ruta)
+		return nil
+	}
+
+	if inodo.I_type != FS.TipoCarpeta {
+		contenido, err := c.v.LeerArchivo(inodo)
+		if err != nil {
+			return fmt.Errorf("error al leer %s: %v", ruta, err)
+		}
+		nCopia, err := c.v.CrearArchivo(nDestino, nombre, c.uid, c.gid, []byte(contenido))
+		if err != nil {
+			return fmt.Errorf("no se pudo copiar %s: %v", ruta, err)
+		}
+		c.creados = append(c.creados, nCopia)
+		c.copiados++
+		return c.conservarPermisos(nCopia, inodo)
+	}
+
+	nCopia, err := c.v.CrearDirectorio(nDestino, nombre, c.uid, c.gid)
+	if err != nil {
+		return fmt.Errorf("no se pudo copiar %s: %v", ruta, err)
+	}
+	c.creados = append(c.creados, nCopia)
+	c.copiados++
+	if err := c.conservarPermisos(nCopia, inodo); err != nil {
+		return err
+	}
+
+	entradas, err := c.v.Entradas(inodo)
+	if err != nil {
+		return fmt.Errorf("error al leer la carpeta %s: %v", ruta, err)
+	}
+	for _, e := range entradas {
+		if e.Nombre == "." || e.Nombre == ".." {
+			continue
+		}
+		hijo, err := c.v.ReadInode(e.Inodo)
+		if err != nil {
+			return err
+		}
+		if err := c.copiarElemento(e.Inodo, hijo, nCopia, e.Nombre, strings.TrimSuffix(ruta, "/")+"/"+e.Nombre); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deshacer elimina lo que alcanzó a crear una copia fallida: quita la entrada
+// nombre de nDestino y libera los inodos creados, hijos antes que padres
+func (c *copiaFS) deshacer(nDestino int64, nombre string) error {
+	if len(c.creados) == 0 {
+		return nil
+	}
+	if err := c.v.QuitarEntrada(nDestino, nombre); err != nil {
+		return err
+	}
+	for i := len(c.creados) - 1; i >= 0; i-- {
+		if err := c.v.EliminarInodo(c.creados[i]); err != nil {
+			return fmt.Errorf("error al liberar el inodo %d: %v", c.creados[i], err)
+		}
+	}
+	fmt.Printf("🔧 DEBUG: COPY deshecha (%d inodos liberados)\n", len(c.creados))
+	return nil
+}
+
+// conservarPermisos copia I_perm del original a la copia
+func (c *copiaFS) conservarPermisos(nCopia int64, original Structs.Inodos) error {
+	copia, err := c.v.ReadInode(nCopia)
+	if err != nil {
+		return err
+	}
+	copia.I_perm = original.I_perm
+	return c.v.WriteInode(nCopia, copia)
+}
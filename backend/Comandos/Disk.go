@@ -1,9 +1,7 @@
 package Comandos
 
 import (
-	"bytes"
 	"crypto/rand"
-	"encoding/binary"
 	"fmt"
 	"math/big"
 	"os"
@@ -137,13 +135,7 @@ func MKDISK(s, f, u, path string) string {
 	copy(mbr.Dsk_fit[:], f)
 
 	// Escribir MBR al inicio del archivo
-	file.Seek(0, 0)
-	var buffer_mbr bytes.Buffer
-	if err := binary.Write(&buffer_mbr, binary.LittleEndian, &mbr); err != nil {
-		return Utils.Error("MKDISK", "Error al escribir MBR: "+err.Error())
-	}
-
-	if _, err := file.Write(buffer_mbr.Bytes()); err != nil {
+	if err := Structs.EscribirEstructura(file, 0, mbr); err != nil {
 		return Utils.Error("MKDISK", "Error al escribir MBR en el archivo: "+err.Error())
 	}
 
@@ -0,0 +1,108 @@
+package Comandos
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosEDIT valida los parámetros del comando EDIT
+func ValidarDatosEDIT(tokens []string) string {
+	path := ""
+	contenido := ""
+	agregar := false
+
+	for _, token := range tokens {
+		lower := strings.ToLower(strings.TrimSpace(token))
+		if lower == "-append" || lower == "append" {
+			agregar = true
+			continue
+		}
+
+		tk := strings.SplitN(token, "=", 2)
+		if len(tk) != 2 {
+			continue
+		}
+		switch strings.ToLower(tk[0]) {
+		case "path":
+			path = strings.ReplaceAll(tk[1], "\"", "")
+		case "contenido":
+			contenido = strings.ReplaceAll(tk[1], "\"", "")
+		default:
+			return Utils.Error("EDIT", "Parámetro no reconocido: "+tk[0])
+		}
+	}
+
+	if path == "" || contenido == "" {
+		return Utils.Error("EDIT", "Los parámetros -path y -contenido son obligatorios")
+	}
+	if !EstaLogueado() {
+		return Utils.Error("EDIT", "Debe iniciar sesión para ejecutar este comando")
+	}
+
+	datos, err := os.ReadFile(contenido)
+	if err != nil {
+		return Utils.Error("EDIT", "No se pudo leer el archivo de contenido: "+err.Error())
+	}
+	return edit(path, datos, agregar)
+}
+
+// edit reemplaza el contenido del archivo path o, con agregar, lo añade al
+// final. Los bloques se ajustan al nuevo tamaño liberando los sobrantes.
+func edit(path string, datos []byte, agregar bool) string {
+	fmt.Printf("🔧 DEBUG: EDIT path='%s' bytes=%d append=%t\n", path, len(datos), agregar)
+
+	sesion := ObtenerSesionActiva()
+	v, err := abrirVolumen("EDIT", sesion.Id)
+	if err != nil {
+		return Utils.Error("EDIT", err.Error())
+	}
+	defer v.Cerrar()
+
+	tamaño, err := contextoSesion(v).editarArchivo(path, datos, agregar)
+	if err != nil {
+		return Utils.Error("EDIT", err.Error())
+	}
+	return Utils.Mensaje("EDIT", fmt.Sprintf("Archivo '%s' editado (%d bytes)", path, tamaño))
+}
+
+// editarArchivo verifica y registra la edición de path y luego escribe el
+// contenido nuevo. Retorna el tamaño final del archivo.
+func (c *contextoFS) editarArchivo(path string, datos []byte, agregar bool) (int64, error) {
+	nInodo, inodo, err := c.v.ResolvePath(path)
+	if err != nil {
+		return 0, err
+	}
+	if inodo.I_type != FS.TipoArchivo {
+		return 0, fmt.Errorf("%s no es un archivo", path)
+	}
+	if !c.puede(inodo, permisoEscritura) {
+		return 0, fmt.Errorf("no tiene permiso de escritura sobre %s", path)
+	}
+
+	nuevo := datos
+	if agregar {
+		actual, err := c.v.LeerArchivo(inodo)
+		if err != nil {
+			return 0, fmt.Errorf("error al leer %s: %v", path, err)
+		}
+		nuevo = append([]byte(actual), datos...)
+	}
+
+	// Registrar la operación en el journal antes de modificar el disco
+	operacion := "edit"
+	if agregar {
+		operacion = "append"
+	}
+	if err := c.registrar(operacion, path, string(datos)); err != nil {
+		return 0, err
+	}
+
+	if err := c.v.EscribirArchivo(nInodo, &inodo, nuevo); err != nil {
+		return 0, fmt.Errorf("no se pudo escribir %s: %v", path, err)
+	}
+	return inodo.I_size, nil
+}
@@ -1,13 +1,10 @@
 package Comandos
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
-	"unsafe"
 
 	"godisk-backend/Structs"
 	"godisk-backend/Utils"
@@ -114,8 +111,17 @@ func ValidarDatosFDISK(tokens []string) string {
 	}
 
 	// Validaciones mejoradas
-	if name == "" || path == "" || size == "" {
-		return Utils.Error("FDISK", "Los parámetros name, path y size son obligatorios")
+	if name == "" || path == "" {
+		return Utils.Error("FDISK", "Los parámetros name y path son obligatorios")
+	}
+	if delete == "" && add == "" && size == "" {
+		return Utils.Error("FDISK", "El parámetro size es obligatorio para crear una partición")
+	}
+	if delete != "" && add != "" {
+		return Utils.Error("FDISK", "Los parámetros delete y add no pueden usarse juntos")
+	}
+	if delete != "" && !Utils.ValidarParametro(delete, []string{"fast", "full"}) {
+		return Utils.Error("FDISK", "Valores válidos para delete: fast, full")
 	}
 
 	validFits := []string{"BF", "FF", "WF"}
@@ -135,7 +141,7 @@ func ValidarDatosFDISK(tokens []string) string {
 
 	// Ejecutar comando según el tipo
 	if delete != "" {
-		return eliminarParticion(path, name)
+		return eliminarParticion(path, name, strings.ToLower(delete))
 	} else if add != "" {
 		return addParticion(path, name, add, unit)
 	} else {
@@ -212,6 +218,17 @@ func FDISK(s, path, name, unit, tipo, fit string) string {
 		}
 	}
 
+	// Las lógicas se ubican dentro de la extendida, no en una entrada del MBR
+	if Utils.Comparar(tipo, "L") {
+		logica := Structs.NewParticion()
+		logica.Part_status = '1'
+		logica.Part_type = 'L'
+		logica.Part_fit = fit[0]
+		logica.Part_size = int64(sizeBytes)
+		copy(logica.Part_name[:], name)
+		return crearLogica(logica, *obtenerParticionExtendida(particiones), path)
+	}
+
 	espaciosLibres := calcularEspaciosLibres(*mbr, particiones)
 	fmt.Printf("🔧 DEBUG: Espacios libres encontrados: %d\n", len(espaciosLibres))
 
@@ -263,13 +280,8 @@ func FDISK(s, path, name, unit, tipo, fit string) string {
 			name, Utils.FormatearTamaño(int64(sizeBytes))))
 	}
 
-	tipoStr := "primaria"
-	if Utils.Comparar(tipo, "L") {
-		tipoStr = "lógica"
-	}
-
-	return Utils.Mensaje("FDISK", fmt.Sprintf("Partición %s '%s' creada correctamente (%s)",
-		tipoStr, name, Utils.FormatearTamaño(int64(sizeBytes))))
+	return Utils.Mensaje("FDISK", fmt.Sprintf("Partición primaria '%s' creada correctamente (%s)",
+		name, Utils.FormatearTamaño(int64(sizeBytes))))
 }
 
 // NUEVAS FUNCIONES DE VALIDACIÓN
@@ -361,7 +373,7 @@ func calcularEspaciosLibres(mbr Structs.MBR, particiones []Structs.Particion) []
 	var ocupados []struct{ inicio, fin int }
 
 	// Agregar el MBR como espacio ocupado
-	mbrSize := int(unsafe.Sizeof(mbr))
+	mbrSize := int(Structs.TamMBR)
 	ocupados = append(ocupados, struct{ inicio, fin int }{0, mbrSize})
 
 	// Agregar particiones activas como espacios ocupados
@@ -453,19 +465,11 @@ func escribirMBR(path string, mbr Structs.MBR) error {
 	}
 	defer file.Close()
 
-	file.Seek(0, 0)
-
-	// FIX: Usar BigEndian para mantener compatibilidad
-	var buffer bytes.Buffer
-	if err := binary.Write(&buffer, binary.BigEndian, &mbr); err != nil {
-		return fmt.Errorf("error al serializar MBR: %v", err)
-	}
-
-	if _, err := file.Write(buffer.Bytes()); err != nil {
+	if err := Structs.EscribirEstructura(file, 0, mbr); err != nil {
 		return fmt.Errorf("error al escribir MBR: %v", err)
 	}
 
-	fmt.Printf("🔧 DEBUG: MBR escrito correctamente (%d bytes)\n", buffer.Len())
+	fmt.Printf("🔧 DEBUG: MBR escrito correctamente (%d bytes)\n", Structs.TamMBR)
 	return nil
 }
 
@@ -480,8 +484,7 @@ func crearEBRInicial(path string, start int) error {
 	ebr := Structs.NewEBR()
 	ebr.Part_start = int64(start)
 
-	file.Seek(int64(start), 0)
-	return binary.Write(file, binary.BigEndian, &ebr)
+	return Structs.EscribirEstructura(file, int64(start), ebr)
 }
 
 // Funciones auxiliares existentes (sin cambios críticos)
@@ -494,13 +497,14 @@ func leerDisco(path string) *Structs.MBR {
 	defer file.Close()
 
 	var mbr Structs.MBR
-	file.Seek(0, 0)
-	// FIX: Usar BigEndian para leer también
-	err = binary.Read(file, binary.BigEndian, &mbr)
-	if err != nil {
+	if err := Structs.LeerEstructura(file, 0, &mbr); err != nil {
 		fmt.Printf("❌ Error al leer MBR: %v\n", err)
 		return nil
 	}
+	if err := mbr.ValidarVersion(); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return nil
+	}
 	return &mbr
 }
 
@@ -524,9 +528,32 @@ func buscarParticiones(mbr Structs.MBR, name string, path string) *Structs.Parti
 			}
 		}
 	}
+
+	// Buscar entre las lógicas de la extendida
+	if extendida := obtenerParticionExtendida(particiones); extendida != nil {
+		for _, ebr := range getLogicas(*extendida, path) {
+			if Utils.Comparar(Utils.ConvertirAString(ebr.Part_name), name) {
+				logica := particionDeEBR(ebr)
+				return &logica
+			}
+		}
+	}
 	return nil
 }
 
+// particionDeEBR representa una lógica como Particion para montarla y
+// formatearla igual que una primaria. Part_start es donde empiezan sus datos.
+func particionDeEBR(ebr Structs.EBR) Structs.Particion {
+	particion := Structs.NewParticion()
+	particion.Part_status = ebr.Part_status
+	particion.Part_type = 'L'
+	particion.Part_fit = ebr.Part_fit
+	particion.Part_start = ebr.Part_start
+	particion.Part_size = ebr.Part_size
+	particion.Part_name = ebr.Part_name
+	return particion
+}
+
 // buscarParticionPorNombre busca una partición por nombre
 func buscarParticionPorNombre(particiones []Structs.Particion, name string) *Structs.Particion {
 	for _, particion := range particiones {
@@ -599,7 +626,7 @@ func ListarParticiones(path string) string {
 	resultado += "📁 PARTICIONES:\n"
 	resultado += "─────────────────────────────────────────────────────\n"
 
-	espacioUsado := int64(unsafe.Sizeof(Structs.MBR{}))
+	espacioUsado := int64(Structs.TamMBR)
 	hayParticiones := false
 
 	for i, particion := range particiones {
@@ -712,19 +739,439 @@ func DebugMBR(path string) string {
 	return resultado
 }
 
-// Funciones pendientes de implementar
-func eliminarParticion(path, name string) string {
-	return Utils.Mensaje("FDISK", "Funcionalidad de eliminación en desarrollo")
+// addParticion agrega (valor positivo) o quita (valor negativo) espacio a una
+// partición. Para crecer solo usa el espacio libre contiguo al final de la
+// partición; al reducir no permite cortar el área usada por su contenido.
+func addParticion(path, name, valor, unit string) string {
+	fmt.Printf("🔧 DEBUG: Modificando partición - Path: %s, Name: %s, Add: %s%s\n", path, name, valor, unit)
+
+	cantidad, err := strconv.Atoi(valor)
+	if err != nil || cantidad == 0 {
+		return Utils.Error("FDISK", "Add debe ser un número entero distinto de 0")
+	}
+	delta := int64(Utils.ConvertirBytes(cantidad, unit))
+
+	if !Utils.ArchivoExiste(path) {
+		return Utils.Error("FDISK", "El disco no existe en la ruta: "+path)
+	}
+	mbr := leerDisco(path)
+	if mbr == nil {
+		return Utils.Error("FDISK", "Error al leer el MBR del disco")
+	}
+
+	// Particiones primarias y extendida
+	slots := []*Structs.Particion{&mbr.Mbr_partition_1, &mbr.Mbr_partition_2, &mbr.Mbr_partition_3, &mbr.Mbr_partition_4}
+	for _, slot := range slots {
+		if slot.Part_status != '1' || !Utils.Comparar(Utils.ConvertirAString(slot.Part_name), name) {
+			continue
+		}
+		if id := buscarParticionMontada(path, Utils.ConvertirAString(slot.Part_name)); id != "" {
+			return Utils.Error("FDISK", fmt.Sprintf("La partición '%s' está montada con el ID %s; desmóntela antes de modificar su tamaño", name, id))
+		}
+		if slot.Part_type == 'E' || slot.Part_type == 'e' {
+			for _, ebr := range getLogicas(*slot, path) {
+				if id := buscarParticionMontada(path, Utils.ConvertirAString(ebr.Part_name)); id != "" {
+					return Utils.Error("FDISK", fmt.Sprintf("La partición lógica '%s' está montada con el ID %s; desmóntela antes de modificar la extendida", Utils.ConvertirAString(ebr.Part_name), id))
+				}
+			}
+		}
+
+		nuevoTamaño := slot.Part_size + delta
+		if delta > 0 {
+			// Debe existir un espacio libre que empiece justo donde termina la partición
+			fin := slot.Part_start + slot.Part_size
+			disponible := int64(0)
+			for _, espacio := range calcularEspaciosLibres(*mbr, getParticiones(*mbr)) {
+				if int64(espacio.inicio) == fin {
+					disponible = int64(espacio.tamaño)
+				}
+			}
+			if delta > disponible {
+				return Utils.Error("FDISK", fmt.Sprintf("No hay espacio libre contiguo suficiente después de '%s' (disponible: %s)", name, Utils.FormatearTamaño(disponible)))
+			}
+		} else {
+			minimo, err := tamañoMinimoParticion(path, *slot)
+			if err != nil {
+				return Utils.Error("FDISK", err.Error())
+			}
+			if nuevoTamaño <= 0 || nuevoTamaño < minimo {
+				return Utils.Error("FDISK", fmt.Sprintf("No se puede reducir '%s' a %d bytes: el mínimo es %d bytes", name, nuevoTamaño, max64(minimo, 1)))
+			}
+		}
+
+		slot.Part_size = nuevoTamaño
+		if err := escribirMBR(path, *mbr); err != nil {
+			return Utils.Error("FDISK", "Error al escribir MBR: "+err.Error())
+		}
+		return Utils.Mensaje("FDISK", fmt.Sprintf("Partición '%s' modificada: nuevo tamaño %s", name, Utils.FormatearTamaño(nuevoTamaño)))
+	}
+
+	// Particiones lógicas
+	extendida := obtenerParticionExtendida(getParticiones(*mbr))
+	if extendida == nil {
+		return Utils.Error("FDISK", "No se encontró la partición: "+name)
+	}
+	cadena, err := leerCadenaEBR(path, *extendida)
+	if err != nil {
+		return Utils.Error("FDISK", "Error al leer las particiones lógicas: "+err.Error())
+	}
+
+	for _, actual := range cadena {
+		if actual.ebr.Part_status != '1' || !Utils.Comparar(Utils.ConvertirAString(actual.ebr.Part_name), name) {
+			continue
+		}
+		if id := buscarParticionMontada(path, Utils.ConvertirAString(actual.ebr.Part_name)); id != "" {
+			return Utils.Error("FDISK", fmt.Sprintf("La partición '%s' está montada con el ID %s; desmóntela antes de modificar su tamaño", name, id))
+		}
+
+		nuevoTamaño := actual.ebr.Part_size + delta
+		if delta > 0 {
+			// Puede crecer hasta el siguiente EBR (que no se mueve) o el final de la extendida
+			limite := extendida.Part_start + extendida.Part_size
+			if actual.ebr.Part_next != -1 {
+				limite = actual.ebr.Part_next
+			}
+			disponible := limite - (actual.ebr.Part_start + actual.ebr.Part_size)
+			if delta > disponible {
+				return Utils.Error("FDISK", fmt.Sprintf("No hay espacio libre contiguo suficiente después de '%s' (disponible: %s)", name, Utils.FormatearTamaño(disponible)))
+			}
+		} else {
+			minimo, err := tamañoMinimoParticion(path, Structs.Particion{Part_start: actual.ebr.Part_start, Part_size: actual.ebr.Part_size})
+			if err != nil {
+				return Utils.Error("FDISK", err.Error())
+			}
+			if nuevoTamaño <= 0 || nuevoTamaño < minimo {
+				return Utils.Error("FDISK", fmt.Sprintf("No se puede reducir '%s' a %d bytes: el mínimo es %d bytes", name, nuevoTamaño, max64(minimo, 1)))
+			}
+		}
+
+		actual.ebr.Part_size = nuevoTamaño
+		if err := escribirEBR(path, actual.posicion, actual.ebr); err != nil {
+			return Utils.Error("FDISK", "Error al escribir EBR: "+err.Error())
+		}
+		return Utils.Mensaje("FDISK", fmt.Sprintf("Partición lógica '%s' modificada: nuevo tamaño %s", name, Utils.FormatearTamaño(nuevoTamaño)))
+	}
+
+	return Utils.Error("FDISK", "No se encontró la partición: "+name)
 }
 
-func addParticion(path, name, valor, unit string) string {
-	return Utils.Mensaje("FDISK", "Funcionalidad de modificación en desarrollo")
+// tamañoMinimoParticion calcula cuánto puede reducirse una partición. Si tiene
+// un sistema de archivos, no se puede cortar el área que ocupan sus estructuras;
+// si es extendida, no se pueden cortar sus EBR ni sus lógicas.
+func tamañoMinimoParticion(path string, particion Structs.Particion) (int64, error) {
+	if particion.Part_type == 'E' || particion.Part_type == 'e' {
+		cadena, err := leerCadenaEBR(path, particion)
+		if err != nil {
+			return 0, fmt.Errorf("error al leer las particiones lógicas: %v", err)
+		}
+		minimo := int64(Structs.TamEBR)
+		for _, e := range cadena {
+			fin := e.posicion + Structs.TamEBR
+			if e.ebr.Part_status == '1' {
+				fin = max64(fin, e.ebr.Part_start+e.ebr.Part_size)
+			}
+			minimo = max64(minimo, fin-particion.Part_start)
+		}
+		return minimo, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	var spr Structs.SuperBloque
+	if err := Structs.LeerEstructura(file, particion.Part_start, &spr); err != nil || spr.S_magic != 0xEF53 {
+		return 0, nil // Sin sistema de archivos
+	}
+	finBloques := spr.S_block_start + spr.S_blocks_count*spr.S_block_size
+	return finBloques - particion.Part_start, nil
+}
+
+// max64 retorna el mayor de dos enteros
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// calcularEspaciosLibresExtendida encuentra los espacios libres dentro de la
+// extendida. Un EBR libre al inicio cuenta como espacio reutilizable.
+func calcularEspaciosLibresExtendida(extended Structs.Particion, cadena []ebrEnDisco) []EspacioLibre {
+	fin := extended.Part_start + extended.Part_size
+	var espacios []EspacioLibre
+	ultimoFin := extended.Part_start
+	for _, e := range cadena {
+		if e.ebr.Part_status != '1' {
+			continue
+		}
+		if e.posicion > ultimoFin {
+			espacios = append(espacios, EspacioLibre{inicio: int(ultimoFin), tamaño: int(e.posicion - ultimoFin)})
+		}
+		ultimoFin = e.ebr.Part_start + e.ebr.Part_size
+	}
+	if fin > ultimoFin {
+		espacios = append(espacios, EspacioLibre{inicio: int(ultimoFin), tamaño: int(fin - ultimoFin)})
+	}
+	return espacios
 }
 
+// crearLogica ubica una partición lógica dentro de la extendida usando el
+// ajuste de la extendida. Cada lógica va precedida por su EBR y los EBR se
+// encadenan por Part_next en orden de posición.
 func crearLogica(particion Structs.Particion, extended Structs.Particion, path string) string {
-	return Utils.Mensaje("FDISK", "Funcionalidad de particiones lógicas en desarrollo")
+	nombre := Utils.ConvertirAString(particion.Part_name)
+
+	cadena, err := leerCadenaEBR(path, extended)
+	if err != nil || len(cadena) == 0 {
+		return Utils.Error("FDISK", fmt.Sprintf("Error al leer las particiones lógicas: %v", err))
+	}
+
+	espacios := calcularEspaciosLibresExtendida(extended, cadena)
+
+	// La lógica necesita espacio para su EBR más sus datos
+	necesario := int(particion.Part_size + Structs.TamEBR)
+	posicion := int64(buscarEspacioConFit(espacios, necesario, fitDeParticion(extended.Part_fit)))
+	if posicion == -1 {
+		return Utils.Error("FDISK", "No hay espacio suficiente en la partición extendida para la lógica")
+	}
+	fmt.Printf("🔧 DEBUG: Lógica '%s' ubicada en %d\n", nombre, posicion)
+
+	nuevo := Structs.NewEBR()
+	nuevo.Part_status = '1'
+	nuevo.Part_fit = particion.Part_fit
+	nuevo.Part_start = posicion + Structs.TamEBR
+	nuevo.Part_size = particion.Part_size
+	nuevo.Part_name = particion.Part_name
+
+	// Enlazar con el EBR anterior (el último que está antes de la posición)
+	anterior := cadena[0]
+	for _, e := range cadena {
+		if e.posicion <= posicion {
+			anterior = e
+		}
+	}
+	if anterior.posicion == posicion {
+		// Se reutiliza el EBR libre del inicio de la extendida
+		nuevo.Part_next = anterior.ebr.Part_next
+	} else {
+		nuevo.Part_next = anterior.ebr.Part_next
+		anterior.ebr.Part_next = posicion
+		if err := escribirEBR(path, anterior.posicion, anterior.ebr); err != nil {
+			return Utils.Error("FDISK", "Error al escribir EBR: "+err.Error())
+		}
+	}
+	if err := escribirEBR(path, posicion, nuevo); err != nil {
+		return Utils.Error("FDISK", "Error al escribir EBR: "+err.Error())
+	}
+
+	return Utils.Mensaje("FDISK", fmt.Sprintf("Partición lógica '%s' creada correctamente (%s)",
+		nombre, Utils.FormatearTamaño(particion.Part_size)))
+}
+
+// fitDeParticion convierte el byte de ajuste al formato de FDISK (FF, BF, WF)
+func fitDeParticion(fit byte) string {
+	switch fit {
+	case 'B', 'b':
+		return "BF"
+	case 'W', 'w':
+		return "WF"
+	default:
+		return "FF"
+	}
 }
 
+// getLogicas retorna las particiones lógicas activas de una extendida
 func getLogicas(particion Structs.Particion, path string) []Structs.EBR {
-	return []Structs.EBR{}
+	var logicas []Structs.EBR
+	cadena, err := leerCadenaEBR(path, particion)
+	if err != nil {
+		fmt.Printf("❌ Error al leer EBR: %v\n", err)
+		return logicas
+	}
+	for _, e := range cadena {
+		if e.ebr.Part_status == '1' {
+			logicas = append(logicas, e.ebr)
+		}
+	}
+	return logicas
+}
+
+// ebrEnDisco es un EBR de la cadena junto con la posición donde está escrito
+type ebrEnDisco struct {
+	posicion int64
+	ebr      Structs.EBR
+}
+
+// leerCadenaEBR recorre la cadena de EBR desde el inicio de la extendida
+// siguiendo Part_next. El primer EBR siempre está al inicio de la extendida.
+func leerCadenaEBR(path string, extendida Structs.Particion) ([]ebrEnDisco, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var cadena []ebrEnDisco
+	fin := extendida.Part_start + extendida.Part_size
+	for pos := extendida.Part_start; pos != -1; {
+		if pos < extendida.Part_start || pos+Structs.TamEBR > fin {
+			return cadena, fmt.Errorf("EBR fuera de la partición extendida en %d", pos)
+		}
+		var ebr Structs.EBR
+		if err := Structs.LeerEstructura(file, pos, &ebr); err != nil {
+			return cadena, err
+		}
+		cadena = append(cadena, ebrEnDisco{posicion: pos, ebr: ebr})
+
+		// Cada EBR debe apuntar hacia adelante; evita ciclos en discos dañados
+		if ebr.Part_next != -1 && ebr.Part_next <= pos {
+			return cadena, fmt.Errorf("cadena de EBR inválida en %d", pos)
+		}
+		pos = ebr.Part_next
+	}
+	return cadena, nil
+}
+
+// escribirEBR escribe un EBR en la posición indicada
+func escribirEBR(path string, pos int64, ebr Structs.EBR) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return Structs.EscribirEstructura(file, pos, ebr)
+}
+
+// escribirCeros llena con ceros el rango [inicio, inicio+tamaño) del disco
+func escribirCeros(path string, inicio, tamaño int64) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ceros := make([]byte, 1024)
+	for escrito := int64(0); escrito < tamaño; {
+		n := int64(len(ceros))
+		if escrito+n > tamaño {
+			n = tamaño - escrito
+		}
+		if _, err := file.WriteAt(ceros[:n], inicio+escrito); err != nil {
+			return err
+		}
+		escrito += n
+	}
+	return nil
+}
+
+// eliminarParticion elimina una partición primaria, extendida o lógica. Con
+// fast solo se libera su entrada; con full además se llena de ceros su espacio.
+func eliminarParticion(path, name, modo string) string {
+	fmt.Printf("🔧 DEBUG: Eliminando partición - Path: %s, Name: %s, Modo: %s\n", path, name, modo)
+
+	if !Utils.ArchivoExiste(path) {
+		return Utils.Error("FDISK", "El disco no existe en la ruta: "+path)
+	}
+	mbr := leerDisco(path)
+	if mbr == nil {
+		return Utils.Error("FDISK", "Error al leer el MBR del disco")
+	}
+
+	// Buscar en las cuatro entradas del MBR
+	slots := []*Structs.Particion{&mbr.Mbr_partition_1, &mbr.Mbr_partition_2, &mbr.Mbr_partition_3, &mbr.Mbr_partition_4}
+	for _, slot := range slots {
+		if slot.Part_status != '1' || !Utils.Comparar(Utils.ConvertirAString(slot.Part_name), name) {
+			continue
+		}
+		if id := buscarParticionMontada(path, Utils.ConvertirAString(slot.Part_name)); id != "" {
+			return Utils.Error("FDISK", fmt.Sprintf("La partición '%s' está montada con el ID %s; desmóntela antes de eliminarla", name, id))
+		}
+
+		esExtendida := slot.Part_type == 'E' || slot.Part_type == 'e'
+		logicas := 0
+		if esExtendida {
+			// La cadena de EBR se elimina junto con la extendida
+			for _, ebr := range getLogicas(*slot, path) {
+				if id := buscarParticionMontada(path, Utils.ConvertirAString(ebr.Part_name)); id != "" {
+					return Utils.Error("FDISK", fmt.Sprintf("La partición lógica '%s' está montada con el ID %s; desmóntela antes de eliminar la extendida", Utils.ConvertirAString(ebr.Part_name), id))
+				}
+				logicas++
+			}
+		}
+
+		if modo == "full" {
+			if err := escribirCeros(path, slot.Part_start, slot.Part_size); err != nil {
+				return Utils.Error("FDISK", "Error al limpiar la partición: "+err.Error())
+			}
+		} else if esExtendida {
+			// En fast basta con invalidar el primer EBR para descartar la cadena
+			if err := escribirCeros(path, slot.Part_start, Structs.TamEBR); err != nil {
+				return Utils.Error("FDISK", "Error al eliminar la cadena de EBR: "+err.Error())
+			}
+		}
+
+		*slot = Structs.NewParticion()
+		if err := escribirMBR(path, *mbr); err != nil {
+			return Utils.Error("FDISK", "Error al escribir MBR: "+err.Error())
+		}
+
+		if esExtendida {
+			return Utils.Mensaje("FDISK", fmt.Sprintf("Partición extendida '%s' eliminada (%s) junto con %d partición(es) lógica(s)", name, modo, logicas))
+		}
+		return Utils.Mensaje("FDISK", fmt.Sprintf("Partición '%s' eliminada (%s)", name, modo))
+	}
+
+	// Buscar entre las lógicas de la extendida
+	extendida := obtenerParticionExtendida(getParticiones(*mbr))
+	if extendida == nil {
+		return Utils.Error("FDISK", "No se encontró la partición: "+name)
+	}
+	cadena, err := leerCadenaEBR(path, *extendida)
+	if err != nil {
+		return Utils.Error("FDISK", "Error al leer las particiones lógicas: "+err.Error())
+	}
+
+	for i, actual := range cadena {
+		if actual.ebr.Part_status != '1' || !Utils.Comparar(Utils.ConvertirAString(actual.ebr.Part_name), name) {
+			continue
+		}
+		if id := buscarParticionMontada(path, Utils.ConvertirAString(actual.ebr.Part_name)); id != "" {
+			return Utils.Error("FDISK", fmt.Sprintf("La partición '%s' está montada con el ID %s; desmóntela antes de eliminarla", name, id))
+		}
+
+		if modo == "full" {
+			if err := escribirCeros(path, actual.ebr.Part_start, actual.ebr.Part_size); err != nil {
+				return Utils.Error("FDISK", "Error al limpiar la partición: "+err.Error())
+			}
+		}
+
+		if i == 0 {
+			// El primer EBR se queda al inicio de la extendida, solo se marca libre
+			libre := Structs.NewEBR()
+			libre.Part_start = actual.ebr.Part_start
+			libre.Part_next = actual.ebr.Part_next
+			if err := escribirEBR(path, actual.posicion, libre); err != nil {
+				return Utils.Error("FDISK", "Error al escribir EBR: "+err.Error())
+			}
+		} else {
+			// Desenlazar el EBR de la cadena
+			anterior := cadena[i-1]
+			anterior.ebr.Part_next = actual.ebr.Part_next
+			if err := escribirEBR(path, anterior.posicion, anterior.ebr); err != nil {
+				return Utils.Error("FDISK", "Error al escribir EBR: "+err.Error())
+			}
+			if modo == "full" {
+				if err := escribirCeros(path, actual.posicion, Structs.TamEBR); err != nil {
+					return Utils.Error("FDISK", "Error al limpiar el EBR: "+err.Error())
+				}
+			}
+		}
+
+		return Utils.Mensaje("FDISK", fmt.Sprintf("Partición lógica '%s' eliminada (%s)", name, modo))
+	}
+
+	return Utils.Error("FDISK", "No se encontró la partición: "+name)
 }
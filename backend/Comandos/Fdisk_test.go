@@ -0,0 +1,122 @@
+package Comandos
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"godisk-backend/Structs"
+)
+
+// ebrPrueba arma un EBR en pos con una lógica de tamaño size (status '0' si
+// size es 0) que apunta a next
+func ebrPrueba(pos, size, next int64) ebrEnDisco {
+	ebr := Structs.NewEBR()
+	if size > 0 {
+		ebr.Part_status = '1'
+		ebr.Part_start = pos + Structs.TamEBR
+		ebr.Part_size = size
+	}
+	ebr.Part_next = next
+	return ebrEnDisco{posicion: pos, ebr: ebr}
+}
+
+func TestLeerCadenaEBR(t *testing.T) {
+	extendida := Structs.NewParticion()
+	extendida.Part_type = 'E'
+	extendida.Part_start, extendida.Part_size = 1000, 1000
+	fin := int(extendida.Part_start + extendida.Part_size)
+
+	casos := []struct {
+		nombre   string
+		ebrs     []ebrEnDisco
+		leidos   int  // EBR que debe retornar la cadena
+		falla    bool // la cadena es inválida
+		espacios []EspacioLibre
+	}{
+		{
+			nombre:   "solo el EBR inicial libre",
+			ebrs:     []ebrEnDisco{ebrPrueba(1000, 0, -1)},
+			leidos:   1,
+			espacios: []EspacioLibre{{inicio: 1000, tamaño: 1000}},
+		},
+		{
+			nombre: "dos lógicas con hueco",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 100, 1300), ebrPrueba(1300, 200, -1)},
+			leidos: 2,
+			espacios: []EspacioLibre{
+				{inicio: 1000 + Structs.TamEBR + 100, tamaño: 1300 - (1000 + Structs.TamEBR + 100)},
+				{inicio: 1300 + Structs.TamEBR + 200, tamaño: fin - (1300 + Structs.TamEBR + 200)},
+			},
+		},
+		{
+			nombre: "EBR inicial libre antes de una lógica",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 0, 1300), ebrPrueba(1300, 200, -1)},
+			leidos: 2,
+			espacios: []EspacioLibre{
+				{inicio: 1000, tamaño: 300},
+				{inicio: 1300 + Structs.TamEBR + 200, tamaño: fin - (1300 + Structs.TamEBR + 200)},
+			},
+		},
+		{
+			nombre: "extendida llena",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 1000-Structs.TamEBR, -1)},
+			leidos: 1,
+		},
+		{
+			nombre: "ciclo hacia el primer EBR",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 100, 1300), ebrPrueba(1300, 200, 1000)},
+			leidos: 2,
+			falla:  true,
+		},
+		{
+			nombre: "EBR que se apunta a sí mismo",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 100, 1000)},
+			leidos: 1,
+			falla:  true,
+		},
+		{
+			nombre: "siguiente fuera de la extendida",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 100, 5000)},
+			leidos: 1,
+			falla:  true,
+		},
+		{
+			nombre: "EBR que no cabe al final",
+			ebrs:   []ebrEnDisco{ebrPrueba(1000, 100, int64(fin)-Structs.TamEBR+1)},
+			leidos: 1,
+			falla:  true,
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "disco.mia")
+			if err := os.WriteFile(path, make([]byte, 3000), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range c.ebrs {
+				if err := escribirEBR(path, e.posicion, e.ebr); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cadena, err := leerCadenaEBR(path, extendida)
+			if (err != nil) != c.falla {
+				t.Fatalf("leerCadenaEBR: error = %v, se esperaba falla = %v", err, c.falla)
+			}
+			if len(cadena) != c.leidos {
+				t.Fatalf("leerCadenaEBR retornó %d EBR, se esperaban %d", len(cadena), c.leidos)
+			}
+			if c.falla {
+				return
+			}
+
+			espacios := calcularEspaciosLibresExtendida(extendida, cadena)
+			if !reflect.DeepEqual(espacios, c.espacios) {
+				t.Errorf("espacios libres = %+v, se esperaban %+v", espacios, c.espacios)
+			}
+		})
+	}
+}
@@ -0,0 +1,374 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Structs"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosFSCK valida los parámetros del comando FSCK
+func ValidarDatosFSCK(tokens []string) string {
+	id := ""
+	reparar := false
+	checkpoint := false
+
+	for _, token := range tokens {
+		lower := strings.ToLower(strings.TrimSpace(token))
+		if lower == "-repair" || lower == "repair" {
+			reparar = true
+			continue
+		}
+		if lower == "-checkpoint" || lower == "checkpoint" {
+			checkpoint = true
+			continue
+		}
+
+		tk := strings.Split(token, "=")
+		if len(tk) != 2 {
+			continue
+		}
+		switch strings.ToLower(tk[0]) {
+		case "id":
+			id = strings.ReplaceAll(tk[1], "\"", "")
+		default:
+			return Utils.Error("FSCK", "Parámetro no reconocido: "+tk[0])
+		}
+	}
+
+	if id == "" {
+		return Utils.Error("FSCK", "El parámetro -id es obligatorio")
+	}
+
+	if checkpoint && !EsUsuarioRoot() {
+		return Utils.Error("FSCK", "Solo el usuario root puede usar -checkpoint")
+	}
+
+	return fsck(id, reparar, checkpoint)
+}
+
+// revisionFS acumula el estado de una revisión de FSCK
+type revisionFS struct {
+	v          *FS.Volumen
+	reparar    bool
+	bmInodos   []byte   // bitmap de inodos leído antes del recorrido
+	inodos     []bool   // inodos alcanzables desde la raíz
+	bloques    []string // ruta del dueño de cada bloque alcanzable
+	dobles     map[int64]bool
+	problemas  []string
+	reparados  int
+	pendientes int
+}
+
+// reportar registra un problema; reparado indica si se corrigió. En modo
+// reparación los problemas que quedan sin corregir se indican como tales.
+func (r *revisionFS) reportar(reparado bool, formato string, args ...interface{}) {
+	estado := "⚠️"
+	mensaje := fmt.Sprintf(formato, args...)
+	if reparado {
+		estado = "🔧"
+		r.reparados++
+	} else {
+		r.pendientes++
+		if r.reparar {
+			mensaje += " (no se puede reparar)"
+		}
+	}
+	r.problemas = append(r.problemas, fmt.Sprintf("%s %s", estado, mensaje))
+}
+
+// fsck revisa la consistencia del sistema de archivos de la partición montada.
+// Con checkpoint, si no quedan problemas sin reparar, vacía el journal.
+func fsck(id string, reparar, checkpoint bool) string {
+	v, err := abrirVolumen("FSCK", id)
+	if err != nil {
+		return Utils.Error("FSCK", err.Error())
+	}
+	defer v.Cerrar()
+
+	bmInodos, err := v.LeerBitmapInodos()
+	if err != nil {
+		return Utils.Error("FSCK", err.Error())
+	}
+
+	r := &revisionFS{
+		v:        v,
+		reparar:  reparar,
+		bmInodos: bmInodos,
+		inodos:   make([]bool, v.Super.S_inodes_count),
+		bloques:  make([]string, v.Super.S_blocks_count),
+		dobles:   map[int64]bool{},
+	}
+
+	// 1. Recorrer el árbol desde la raíz
+	r.revisarInodo(0, 0, "/", nil)
+
+	// 2. Comparar con los bitmaps
+	bmBloques, err := v.LeerBitmapBloques()
+	if err != nil {
+		return Utils.Error("FSCK", err.Error())
+	}
+
+	cambioInodos := r.compararBitmap("inodo", bmInodos, func(i int) bool { return r.inodos[i] })
+	cambioBloques := r.compararBitmap("bloque", bmBloques, func(i int) bool { return r.bloques[i] != "" })
+
+	if reparar && cambioInodos {
+		if err := v.EscribirBitmapInodos(bmInodos); err != nil {
+			return Utils.Error("FSCK", err.Error())
+		}
+	}
+	if reparar && cambioBloques {
+		if err := v.EscribirBitmapBloques(bmBloques); err != nil {
+			return Utils.Error("FSCK", err.Error())
+		}
+	}
+
+	// 3. Recalcular contadores libres
+	libresInodos, libresBloques := int64(0), int64(0)
+	for _, alcanzado := range r.inodos {
+		if !alcanzado {
+			libresInodos++
+		}
+	}
+	for _, dueño := range r.bloques {
+		if dueño == "" {
+			libresBloques++
+		}
+	}
+	if v.Super.S_free_inodes_count != libresInodos {
+		r.reportar(reparar, "S_free_inodes_count es %d y debería ser %d", v.Super.S_free_inodes_count, libresInodos)
+		v.Super.S_free_inodes_count = libresInodos
+	}
+	if v.Super.S_free_blocks_count != libresBloques {
+		r.reportar(reparar, "S_free_blocks_count es %d y debería ser %d", v.Super.S_free_blocks_count, libresBloques)
+		v.Super.S_free_blocks_count = libresBloques
+	}
+	if reparar {
+		// Los primeros libres se recalculan desde los bitmaps ya corregidos
+		v.Super.S_firts_ino = FS.PrimerLibre(bmInodos)
+		v.Super.S_first_blo = FS.PrimerLibre(bmBloques)
+		if err := v.WriteSuperblock(); err != nil {
+			return Utils.Error("FSCK", err.Error())
+		}
+	}
+
+	// Armar el reporte
+	var sb strings.Builder
+	modo := "revisión"
+	if reparar {
+		modo = "reparación"
+	}
+	sb.WriteString(fmt.Sprintf("FSCK (%s) de la partición %s\n", modo, id))
+	sb.WriteString(fmt.Sprintf("   Inodos alcanzables: %d de %d\n", v.Super.S_inodes_count-libresInodos, v.Super.S_inodes_count))
+	sb.WriteString(fmt.Sprintf("   Bloques alcanzables: %d de %d\n", v.Super.S_blocks_count-libresBloques, v.Super.S_blocks_count))
+	if len(r.problemas) == 0 {
+		sb.WriteString("   Sin inconsistencias")
+	} else {
+		for _, p := range r.problemas {
+			sb.WriteString("   " + p + "\n")
+		}
+		sb.WriteString(fmt.Sprintf("   Problemas: %d, reparados: %d, sin reparar: %d", len(r.problemas), r.reparados, r.pendientes))
+	}
+
+	if checkpoint {
+		switch {
+		case !v.EsEXT3():
+			sb.WriteString("\n   ⚠️ La partición es EXT2 y no tiene journal")
+		case r.pendientes > 0:
+			sb.WriteString("\n   ⚠️ El journal no se vació porque quedan problemas sin reparar")
+		default:
+			liberadas, err := v.VaciarJournal()
+			if err != nil {
+				return Utils.Error("FSCK", err.Error())
+			}
+			sb.WriteString(fmt.Sprintf("\n   Punto de control: journal vaciado (%d entradas liberadas)", liberadas))
+		}
+	}
+	return Utils.Mensaje("FSCK", sb.String())
+}
+
+// revisarInodo marca el inodo n y sus bloques como alcanzables y, si es una
+// carpeta, valida "." y ".." y recorre sus entradas. entrada es la entrada
+// de carpeta que lo referencia (nil para la raíz); en modo reparación se
+// elimina si el inodo es inválido o ya estaba referenciado.
+func (r *revisionFS) revisarInodo(n, padre int64, ruta string, entrada *FS.Entrada) {
+	if r.inodos[n] {
+		reparado := r.reparar && entrada != nil && r.limpiarEntrada(*entrada)
+		r.reportar(reparado, "El inodo %d (%s) está referenciado más de una vez", n, ruta)
+		return
+	}
+	r.inodos[n] = true
+
+	inodo, err := r.v.ReadInode(n)
+	if err != nil {
+		r.reportar(false, "No se pudo leer el inodo %d (%s): %v", n, ruta, err)
+		return
+	}
+	if problema := r.validarInodo(n, inodo); problema != "" {
+		// Sin la entrada el inodo queda huérfano y el bitmap lo libera
+		reparado := r.reparar && entrada != nil && r.limpiarEntrada(*entrada)
+		if reparado {
+			r.inodos[n] = false
+		}
+		r.reportar(reparado, "El inodo %d (%s) %s", n, ruta, problema)
+		return
+	}
+
+	// Bloques de datos y de apuntadores
+	datos, err := r.v.BloquesDeInodo(inodo)
+	if err != nil {
+		r.reportar(false, "El inodo %d (%s) tiene apuntadores inválidos: %v", n, ruta, err)
+		return
+	}
+	apuntadores, _ := r.v.BloquesApuntadoresDeInodo(inodo)
+	for _, b := range append(datos, apuntadores...) {
+		if b < 0 || b >= int64(len(r.bloques)) {
+			r.reportar(false, "El inodo %d (%s) apunta al bloque fuera de rango %d", n, ruta, b)
+			continue
+		}
+		if r.bloques[b] != "" {
+			if !r.dobles[b] {
+				r.reportar(false, "El bloque %d está asignado a %s y a %s", b, r.bloques[b], ruta)
+				r.dobles[b] = true
+			}
+			continue
+		}
+		r.bloques[b] = ruta
+	}
+
+	if inodo.I_type != FS.TipoCarpeta {
+		return
+	}
+	r.revisarPuntos(n, padre, ruta, datos)
+
+	entradas, err := r.v.Entradas(inodo)
+	if err != nil {
+		r.reportar(false, "No se pudieron leer las entradas de %s: %v", ruta, err)
+		return
+	}
+	for _, e := range entradas {
+		if e.Nombre == "." || e.Nombre == ".." {
+			continue
+		}
+		hijo := strings.TrimSuffix(ruta, "/") + "/" + e.Nombre
+		if e.Inodo < 0 || e.Inodo >= int64(len(r.inodos)) {
+			reparado := r.reparar && r.limpiarEntrada(e)
+			r.reportar(reparado, "La entrada %s apunta al inodo fuera de rango %d", hijo, e.Inodo)
+			continue
+		}
+		r.revisarInodo(e.Inodo, n, hijo, &e)
+	}
+}
+
+// validarInodo retorna por qué no se puede confiar en el inodo n, o "" si es
+// válido: debe estar marcado en el bitmap, tener fecha de creación, apuntadores
+// -1 o dentro del área de bloques y un tipo conocido. Un inodo en cero (por
+// ejemplo, tras LOSS) tiene el tipo de carpeta pero no cumple lo demás.
+func (r *revisionFS) validarInodo(n int64, inodo Structs.Inodos) string {
+	if r.bmInodos[n] != FS.BitmapOcupado || inodo.I_ctime == [16]byte{} {
+		return "no está asignado"
+	}
+	for i, b := range inodo.I_block {
+		if b != -1 && (b < 0 || b >= r.v.Super.S_blocks_count) {
+			return fmt.Sprintf("tiene el apuntador I_block[%d] fuera de rango: %d", i, b)
+		}
+	}
+	if inodo.I_type != FS.TipoCarpeta && inodo.I_type != FS.TipoArchivo {
+		return fmt.Sprintf("tiene un tipo inválido: %d", inodo.I_type)
+	}
+	return ""
+}
+
+// revisarPuntos valida que el primer bloque de la carpeta tenga "." -> n y
+// ".." -> padre, corrigiéndolos en modo reparación
+func (r *revisionFS) revisarPuntos(n, padre int64, ruta string, bloques []int64) {
+	if len(bloques) == 0 {
+		r.reportar(false, "La carpeta %s (inodo %d) no tiene bloques", ruta, n)
+		return
+	}
+
+	var carpeta Structs.BloquesCarpetas
+	if err := r.v.ReadBlock(bloques[0], &carpeta); err != nil {
+		r.reportar(false, "No se pudo leer el bloque %d de %s: %v", bloques[0], ruta, err)
+		return
+	}
+
+	esperados := []struct {
+		nombre string
+		inodo  int64
+	}{{".", n}, {"..", padre}}
+
+	modificado := false
+	for i, esp := range esperados {
+		c := carpeta.B_content[i]
+		if FS.NombreEntrada(c) == esp.nombre && c.B_inodo == esp.inodo {
+			continue
+		}
+		r.reportar(r.reparar, "La entrada '%s' de %s apunta a %d y debería apuntar a %d", esp.nombre, ruta, c.B_inodo, esp.inodo)
+		if r.reparar {
+			carpeta.B_content[i] = Structs.NewContent()
+			copy(carpeta.B_content[i].B_name[:], esp.nombre)
+			carpeta.B_content[i].B_inodo = esp.inodo
+			modificado = true
+		}
+	}
+
+	if modificado {
+		if err := r.v.WriteBlock(bloques[0], carpeta); err != nil {
+			r.reportar(false, "No se pudo corregir %s: %v", ruta, err)
+		}
+	}
+}
+
+// limpiarEntrada elimina una entrada de carpeta inválida
+func (r *revisionFS) limpiarEntrada(e FS.Entrada) bool {
+	var carpeta Structs.BloquesCarpetas
+	if err := r.v.ReadBlock(e.Bloque, &carpeta); err != nil {
+		return false
+	}
+	carpeta.B_content[e.Indice] = Structs.NewContent()
+	return r.v.WriteBlock(e.Bloque, carpeta) == nil
+}
+
+// compararBitmap compara el bitmap con lo alcanzado en el recorrido. Las
+// entradas ocupadas no alcanzables son huérfanas; las alcanzables libres están
+// en uso sin marcar. En modo reparación corrige el bitmap en memoria y
+// retorna si hubo cambios.
+func (r *revisionFS) compararBitmap(tipo string, bitmap []byte, alcanzable func(i int) bool) bool {
+	cambio := false
+	invalidos, primero := 0, 0
+	for i := range bitmap {
+		ocupado := bitmap[i] == FS.BitmapOcupado
+		switch {
+		case alcanzable(i) && !ocupado:
+			r.reportar(r.reparar, "El %s %d está en uso pero libre en el bitmap", tipo, i)
+			if r.reparar {
+				bitmap[i] = FS.BitmapOcupado
+				cambio = true
+			}
+		case !alcanzable(i) && ocupado:
+			r.reportar(r.reparar, "El %s %d está marcado en el bitmap pero es huérfano", tipo, i)
+			if r.reparar {
+				bitmap[i] = FS.BitmapLibre
+				cambio = true
+			}
+		case !alcanzable(i) && bitmap[i] != FS.BitmapLibre:
+			// Valores que no son '0' ni '1' (por ejemplo, tras LOSS); se
+			// reportan juntos para no repetir una línea por entrada
+			if invalidos == 0 {
+				primero = i
+			}
+			invalidos++
+			if r.reparar {
+				bitmap[i] = FS.BitmapLibre
+				cambio = true
+			}
+		}
+	}
+	if invalidos > 0 {
+		r.reportar(r.reparar, "El bitmap de %ss tiene %d valores que no son '0' ni '1' (desde el %s %d)", tipo, invalidos, tipo, primero)
+	}
+	return cambio
+}
@@ -1,15 +1,10 @@
 package Comandos
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"unsafe"
 
-	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
 
@@ -166,50 +161,27 @@ func mkgrp(nombre string) string {
 
 	sesion := ObtenerSesionActiva()
 
-	// Obtener la partición montada de la sesión activa
-	var pathDisco string
-	particion := GetMount("MKGRP", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("MKGRP", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo para lectura primero
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición de la sesión activa
+	v, err := abrirVolumen("MKGRP", sesion.Id)
 	if err != nil {
-		return Utils.Error("MKGRP", "No se encontró el disco: "+err.Error())
-	}
-	defer file.Close()
-
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytesGroups(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		return Utils.Error("MKGRP", "Error al leer superbloque: "+err_.Error())
+		return Utils.Error("MKGRP", err.Error())
 	}
+	defer v.Cerrar()
 
-	fmt.Printf("🔧 DEBUG: SuperBloque leído correctamente\n")
-
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytesGroups(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		return Utils.Error("MKGRP", "Error al leer inodo users.txt: "+err_.Error())
+	if err := contextoSesion(v).crearGrupo(nombre); err != nil {
+		return Utils.Error("MKGRP", err.Error())
 	}
 
-	fmt.Printf("🔧 DEBUG: Inodo users.txt leído - Tamaño: %d\n", inodo.I_size)
+	fmt.Printf("✅ MKGRP: Grupo '%s' creado correctamente\n", nombre)
+	return Utils.Mensaje("MKGRP", fmt.Sprintf("Grupo '%s' creado correctamente", nombre))
+}
 
-	// ✅ USAR FUNCIÓN DE LOGIN.GO - Leer contenido actual de users.txt
-	contenidoActual := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoActual == "" {
-		return Utils.Error("MKGRP", "No se pudo leer el archivo users.txt")
+// crearGrupo agrega el grupo nombre a users.txt con el siguiente ID libre
+func (c *contextoFS) crearGrupo(nombre string) error {
+	// Leer contenido actual de users.txt
+	nUsers, inodo, contenidoActual, err := leerUsuarios(c.v)
+	if err != nil || contenidoActual == "" {
+		return fmt.Errorf("no se pudo leer el archivo users.txt")
 	}
 
 	fmt.Printf("🔧 DEBUG: Contenido actual users.txt:\n%s\n", contenidoActual)
@@ -238,7 +210,7 @@ func mkgrp(nombre string) string {
 
 				nombreGrupo := strings.TrimSpace(campos[2])
 				if nombreGrupo == nombre {
-					return Utils.Error("MKGRP", "El grupo '"+nombre+"' ya existe")
+					return fmt.Errorf("el grupo '%s' ya existe", nombre)
 				}
 			}
 		}
@@ -253,13 +225,17 @@ func mkgrp(nombre string) string {
 
 	fmt.Printf("🔧 DEBUG: Nuevo contenido users.txt:\n%s\n", nuevoContenido)
 
+	// Registrar la operación en el journal antes de modificar users.txt
+	if err := c.registrar("mkgrp", "/users.txt", nombre); err != nil {
+		return err
+	}
+
 	// Escribir el contenido actualizado
-	if err := escribirContenidoArchivo(pathDisco, *particion, super, inodo, nuevoContenido); err != nil {
-		return Utils.Error("MKGRP", "Error al escribir en users.txt: "+err.Error())
+	if err := c.v.EscribirArchivo(nUsers, &inodo, []byte(nuevoContenido)); err != nil {
+		return fmt.Errorf("error al escribir en users.txt: %v", err)
 	}
 
-	fmt.Printf("✅ MKGRP: Grupo '%s' creado correctamente\n", nombre)
-	return Utils.Mensaje("MKGRP", fmt.Sprintf("Grupo '%s' creado correctamente", nombre))
+	return nil
 }
 
 // rmgrp elimina un grupo del sistema
@@ -268,46 +244,27 @@ func rmgrp(nombre string) string {
 
 	sesion := ObtenerSesionActiva()
 
-	// Obtener la partición montada de la sesión activa
-	var pathDisco string
-	particion := GetMount("RMGRP", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("RMGRP", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo para lectura primero
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición de la sesión activa
+	v, err := abrirVolumen("RMGRP", sesion.Id)
 	if err != nil {
-		return Utils.Error("RMGRP", "No se encontró el disco: "+err.Error())
+		return Utils.Error("RMGRP", err.Error())
 	}
-	defer file.Close()
+	defer v.Cerrar()
 
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytesGroups(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		return Utils.Error("RMGRP", "Error al leer superbloque: "+err_.Error())
+	if err := contextoSesion(v).eliminarGrupo(nombre); err != nil {
+		return Utils.Error("RMGRP", err.Error())
 	}
 
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytesGroups(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		return Utils.Error("RMGRP", "Error al leer inodo users.txt: "+err_.Error())
-	}
+	fmt.Printf("✅ RMGRP: Grupo '%s' eliminado correctamente\n", nombre)
+	return Utils.Mensaje("RMGRP", fmt.Sprintf("Grupo '%s' eliminado correctamente", nombre))
+}
 
-	// ✅ USAR FUNCIÓN DE LOGIN.GO - Leer contenido actual de users.txt
-	contenidoActual := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoActual == "" {
-		return Utils.Error("RMGRP", "No se pudo leer el archivo users.txt")
+// eliminarGrupo marca el grupo nombre como eliminado (ID 0) en users.txt
+func (c *contextoFS) eliminarGrupo(nombre string) error {
+	// Leer contenido actual de users.txt
+	nUsers, inodo, contenidoActual, err := leerUsuarios(c.v)
+	if err != nil || contenidoActual == "" {
+		return fmt.Errorf("no se pudo leer el archivo users.txt")
 	}
 
 	fmt.Printf("🔧 DEBUG: Contenido actual users.txt:\n%s\n", contenidoActual)
@@ -339,7 +296,7 @@ func rmgrp(nombre string) string {
 	}
 
 	if !grupoEncontrado {
-		return Utils.Error("RMGRP", "No se encontró el grupo '"+nombre+"'")
+		return fmt.Errorf("no se encontró el grupo '%s'", nombre)
 	}
 
 	// Reconstruir contenido
@@ -347,13 +304,17 @@ func rmgrp(nombre string) string {
 
 	fmt.Printf("🔧 DEBUG: Nuevo contenido users.txt:\n%s\n", nuevoContenido)
 
+	// Registrar la operación en el journal antes de modificar users.txt
+	if err := c.registrar("rmgrp", "/users.txt", nombre); err != nil {
+		return err
+	}
+
 	// Escribir el contenido actualizado
-	if err := escribirContenidoArchivo(pathDisco, *particion, super, inodo, nuevoContenido); err != nil {
-		return Utils.Error("RMGRP", "Error al escribir en users.txt: "+err.Error())
+	if err := c.v.EscribirArchivo(nUsers, &inodo, []byte(nuevoContenido)); err != nil {
+		return fmt.Errorf("error al escribir en users.txt: %v", err)
 	}
 
-	fmt.Printf("✅ RMGRP: Grupo '%s' eliminado correctamente\n", nombre)
-	return Utils.Mensaje("RMGRP", fmt.Sprintf("Grupo '%s' eliminado correctamente", nombre))
+	return nil
 }
 
 // chgrp cambia el grupo de un usuario en users.txt
@@ -362,50 +323,27 @@ func chgrp(usuario, grupo string) string {
 
 	sesion := ObtenerSesionActiva()
 
-	// Obtener la partición montada de la sesión activa
-	var pathDisco string
-	particion := GetMount("CHGRP", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("CHGRP", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo para lectura primero
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición de la sesión activa
+	v, err := abrirVolumen("CHGRP", sesion.Id)
 	if err != nil {
-		return Utils.Error("CHGRP", "No se encontró el disco: "+err.Error())
+		return Utils.Error("CHGRP", err.Error())
 	}
-	defer file.Close()
-
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytesGroups(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		return Utils.Error("CHGRP", "Error al leer superbloque: "+err_.Error())
-	}
-
-	fmt.Printf("🔧 DEBUG: SuperBloque leído correctamente\n")
+	defer v.Cerrar()
 
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytesGroups(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		return Utils.Error("CHGRP", "Error al leer inodo users.txt: "+err_.Error())
+	if err := contextoSesion(v).cambiarGrupo(usuario, grupo); err != nil {
+		return Utils.Error("CHGRP", err.Error())
 	}
 
-	fmt.Printf("🔧 DEBUG: Inodo users.txt leído - Tamaño: %d\n", inodo.I_size)
+	fmt.Printf("✅ CHGRP: Grupo del usuario '%s' cambiado a '%s' correctamente\n", usuario, grupo)
+	return Utils.Mensaje("CHGRP", fmt.Sprintf("Grupo del usuario '%s' cambiado a '%s'", usuario, grupo))
+}
 
-	// Leer contenido actual
-	contenidoActual := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoActual == "" {
-		return Utils.Error("CHGRP", "No se pudo leer el archivo users.txt")
+// cambiarGrupo reemplaza el grupo del usuario en users.txt
+func (c *contextoFS) cambiarGrupo(usuario, grupo string) error {
+	// Leer contenido actual de users.txt
+	nUsers, inodo, contenidoActual, err := leerUsuarios(c.v)
+	if err != nil || contenidoActual == "" {
+		return fmt.Errorf("no se pudo leer el archivo users.txt")
 	}
 
 	fmt.Printf("🔧 DEBUG: Contenido actual users.txt:\n%s\n", contenidoActual)
@@ -445,10 +383,10 @@ func chgrp(usuario, grupo string) string {
 	}
 
 	if !usuarioEncontrado {
-		return Utils.Error("CHGRP", "No se encontró el usuario '"+usuario+"'.")
+		return fmt.Errorf("no se encontró el usuario '%s'", usuario)
 	}
 	if !grupoExiste {
-		return Utils.Error("CHGRP", "No se encontró el grupo '"+grupo+"'.")
+		return fmt.Errorf("no se encontró el grupo '%s'", grupo)
 	}
 
 	// Modificar la línea del usuario (reconstruir con validaciones)
@@ -481,18 +419,15 @@ func chgrp(usuario, grupo string) string {
 
 	fmt.Printf("🔧 DEBUG: Nuevo contenido users.txt:\n%s\n", nuevoContenido)
 
-	// Escribir cambios con la función compartida
-	if err := escribirContenidoArchivo(pathDisco, *particion, super, inodo, nuevoContenido); err != nil {
-		return Utils.Error("CHGRP", "Error al escribir en users.txt: "+err.Error())
+	// Registrar la operación en el journal antes de modificar users.txt
+	if err := c.registrar("chgrp", "/users.txt", usuario+","+grupo); err != nil {
+		return err
 	}
 
-	fmt.Printf("✅ CHGRP: Grupo del usuario '%s' cambiado a '%s' correctamente\n", usuario, grupo)
-	return Utils.Mensaje("CHGRP", fmt.Sprintf("Grupo del usuario '%s' cambiado a '%s'", usuario, grupo))
-}
+	// Escribir cambios con la función compartida
+	if err := c.v.EscribirArchivo(nUsers, &inodo, []byte(nuevoContenido)); err != nil {
+		return fmt.Errorf("error al escribir en users.txt: %v", err)
+	}
 
-// leerBytesGroups función auxiliar para leer bytes del archivo (renombrada para evitar conflictos)
-func leerBytesGroups(file *os.File, size int) []byte {
-	bytes := make([]byte, size)
-	file.Read(bytes)
-	return bytes
+	return nil
 }
@@ -1,14 +1,11 @@
 package Comandos
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"unsafe"
 
+	"godisk-backend/FS"
 	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
@@ -83,54 +80,20 @@ func ValidarDatosLOGIN(tokens []string) string {
 func login(usuario, password, idParticion string) bool {
 	fmt.Printf("🔧 DEBUG: Intentando login - Usuario: %s, ID: %s\n", usuario, idParticion)
 
-	// Obtener la partición montada usando la función GetMount de Mount.go
-	var pathDisco string
-	particion := GetMount("LOGIN", idParticion, &pathDisco)
-	if particion == nil {
-		fmt.Printf("❌ LOGIN: Partición %s no está montada\n", idParticion)
-		return false
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo del disco
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición montada
+	v, err := abrirVolumen("LOGIN", idParticion)
 	if err != nil {
-		fmt.Printf("❌ LOGIN: No se encontró el disco: %v\n", err)
-		return false
-	}
-	defer file.Close()
-
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytes(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		fmt.Printf("❌ LOGIN: Error al leer superbloque: %v\n", err_)
+		fmt.Printf("❌ LOGIN: %v\n", err)
 		return false
 	}
+	defer v.Cerrar()
 
-	fmt.Printf("🔧 DEBUG: SuperBloque leído - FS: %d\n", super.S_filesystem_type)
-
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytes(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		fmt.Printf("❌ LOGIN: Error al leer inodo users.txt: %v\n", err_)
-		return false
-	}
-
-	fmt.Printf("🔧 DEBUG: Inodo users.txt - Tipo: %d, Tamaño: %d\n", inodo.I_type, inodo.I_size)
+	fmt.Printf("🔧 DEBUG: SuperBloque leído - FS: %d\n", v.Super.S_filesystem_type)
 
 	// Leer contenido del archivo users.txt
-	contenidoUsers := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoUsers == "" {
-		fmt.Printf("❌ LOGIN: No se pudo leer el archivo users.txt\n")
+	_, _, contenidoUsers, err := leerUsuarios(v)
+	if err != nil || contenidoUsers == "" {
+		fmt.Printf("❌ LOGIN: No se pudo leer el archivo users.txt: %v\n", err)
 		return false
 	}
 
@@ -140,43 +103,20 @@ func login(usuario, password, idParticion string) bool {
 	return verificarCredencialesLogin(usuario, password, contenidoUsers, idParticion)
 }
 
-// leerContenidoUsersArchivo lee el contenido completo del archivo users.txt
-func leerContenidoUsersArchivo(file *os.File, super Structs.SuperBloque, inodo Structs.Inodos) string {
-	var contenido strings.Builder
-
-	// Calcular posición de los bloques de archivos
-	mitadBA := (super.S_block_start + int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))) // Después del bloque 0 (directorio)
-	tamBA := int64(unsafe.Sizeof(Structs.BloquesArchivos{}))
-
-	// Leer todos los bloques del archivo
-	for bloque := 0; bloque < 16; bloque++ {
-		if inodo.I_block[bloque] == -1 {
-			break
-		}
-
-		// Calcular posición del bloque
-		posicionBloque := mitadBA + (int64(inodo.I_block[bloque]-1) * tamBA)
-
-		file.Seek(posicionBloque, 0)
-		data := leerBytes(file, int(tamBA))
-		buffer := bytes.NewBuffer(data)
+// leerUsuarios lee /users.txt y retorna su número de inodo, el inodo y el contenido
+func leerUsuarios(v *FS.Volumen) (int64, Structs.Inodos, string, error) {
+	nInodo, inodo, err := v.ResolvePath("/users.txt")
+	if err != nil {
+		return -1, inodo, "", err
+	}
 
-		var bloqueArchivo Structs.BloquesArchivos
-		err := binary.Read(buffer, binary.BigEndian, &bloqueArchivo)
-		if err != nil {
-			fmt.Printf("❌ Error al leer bloque de archivo: %v\n", err)
-			break
-		}
+	fmt.Printf("🔧 DEBUG: Inodo users.txt - Tipo: %d, Tamaño: %d\n", inodo.I_type, inodo.I_size)
 
-		// Extraer contenido del bloque
-		for i := 0; i < len(bloqueArchivo.B_content); i++ {
-			if bloqueArchivo.B_content[i] != 0 {
-				contenido.WriteByte(bloqueArchivo.B_content[i])
-			}
-		}
+	contenido, err := v.LeerArchivo(inodo)
+	if err != nil {
+		return -1, inodo, "", err
 	}
-
-	return contenido.String()
+	return nInodo, inodo, contenido, nil
 }
 
 // verificarCredencialesLogin verifica usuario y contraseña en el contenido de users.txt
@@ -269,6 +209,32 @@ func buscarGIDGrupo(nombreGrupo, contenidoUsers string) int {
 	return -1 // No encontrado
 }
 
+// nombresUsuarios indexa por ID los nombres de usuarios y grupos activos de users.txt
+func nombresUsuarios(contenidoUsers string) (map[int64]string, map[int64]string) {
+	usuarios := map[int64]string{}
+	grupos := map[int64]string{}
+
+	for _, linea := range strings.Split(strings.TrimSpace(contenidoUsers), "\n") {
+		campos := strings.Split(strings.TrimSpace(linea), ",")
+		if len(campos) < 3 || campos[0] == "0" {
+			continue
+		}
+		id, err := strconv.ParseInt(campos[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch strings.ToUpper(campos[1]) {
+		case "G":
+			grupos[id] = campos[2]
+		case "U":
+			if len(campos) >= 4 {
+				usuarios[id] = campos[3]
+			}
+		}
+	}
+	return usuarios, grupos
+}
+
 // LOGOUT cierra la sesión activa
 func ValidarDatosLOGOUT(tokens []string) string {
 	return logout()
@@ -325,10 +291,3 @@ func MostrarInfoSesion() string {
 
 	return info
 }
-
-// leerBytes función auxiliar para leer bytes del archivo
-func leerBytes(file *os.File, size int) []byte {
-	bytes := make([]byte, size)
-	file.Read(bytes)
-	return bytes
-}
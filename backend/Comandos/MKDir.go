@@ -1,14 +1,10 @@
 package Comandos
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
-	"os"
 	"strings"
-	"unsafe"
 
-	"godisk-backend/Structs"
+	"godisk-backend/FS"
 	"godisk-backend/Utils"
 )
 
@@ -94,299 +90,151 @@ func mkdir(path string, crearPadres bool) string {
 	fmt.Printf("🔧 DEBUG: MKDIR path='%s' -p=%t\n", path, crearPadres)
 
 	sesion := ObtenerSesionActiva()
-	var pathDisco string
-	particion := GetMount("MKDIR", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("MKDIR", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	filePath := strings.ReplaceAll(pathDisco, "\"", "")
-	file, err := os.OpenFile(filePath, os.O_RDWR, 0644)
+	v, err := abrirVolumen("MKDIR", sesion.Id)
 	if err != nil {
-		return Utils.Error("MKDIR", "No se pudo abrir el disco: "+err.Error())
+		return Utils.Error("MKDIR", err.Error())
 	}
-	defer file.Close()
+	defer v.Cerrar()
 
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	var bufSuper bytes.Buffer
-	readSize := int(unsafe.Sizeof(Structs.SuperBloque{}))
-	tmp := make([]byte, readSize)
-	if _, err := file.Read(tmp); err != nil {
-		return Utils.Error("MKDIR", "Error al leer superbloque: "+err.Error())
-	}
-	bufSuper.Write(tmp)
-	if err := binary.Read(&bufSuper, binary.BigEndian, &super); err != nil {
-		return Utils.Error("MKDIR", "Error al decodificar superbloque: "+err.Error())
+	if err := contextoSesion(v).crearCarpeta(path, crearPadres); err != nil {
+		return Utils.Error("MKDIR", err.Error())
 	}
+	return Utils.Mensaje("MKDIR", fmt.Sprintf("Directorio '%s' creado correctamente", path))
+}
 
-	// Tamaños y punteros
-	tamInodo := int(unsafe.Sizeof(Structs.Inodos{}))
-	tamBloque := int(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	inodoStart := super.S_inode_start
-	blockStart := super.S_block_start
-	bmInodosStart := super.S_bm_inode_start
-	bmBloquesStart := super.S_bm_block_start
-
+// crearCarpeta verifica la ruta, registra la operación en el journal y crea
+// las carpetas con el usuario del contexto como propietario
+func (c *contextoFS) crearCarpeta(path string, crearPadres bool) error {
 	// Normalizar path y obtener componentes
 	trimmed := strings.TrimSpace(path)
 	if trimmed == "" || !strings.HasPrefix(trimmed, "/") {
-		return Utils.Error("MKDIR", "Ruta inválida")
+		return fmt.Errorf("ruta inválida")
+	}
+	componentes := FS.SepararRuta(trimmed)
+	if len(componentes) == 0 {
+		return fmt.Errorf("ruta inválida")
 	}
-	components := strings.Split(trimmed, "/")[1:] // eliminar primer elemento vacío
 
-	// Leer inodo raíz (índice 0)
-	var inode Structs.Inodos
-	file.Seek(inodoStart, 0)
-	dataInodo := make([]byte, tamInodo)
-	if _, err := file.Read(dataInodo); err != nil {
-		return Utils.Error("MKDIR", "Error al leer inodo raíz: "+err.Error())
+	if _, _, err := c.v.ResolvePath(trimmed); err == nil {
+		return fmt.Errorf("ya existe la ruta: %s", path)
 	}
-	if err := binary.Read(bytes.NewBuffer(dataInodo), binary.BigEndian, &inode); err != nil {
-		return Utils.Error("MKDIR", "Error al decodificar inodo raíz: "+err.Error())
+	if err := verificarRutaNueva(c.v, componentes, crearPadres, -1); err != nil {
+		return err
 	}
 
-	currentInode := inode
-	currentInodeOffset := inodoStart // posición del inodo actual en disco (int64)
+	// Registrar la operación en el journal antes de modificar el disco
+	if err := c.registrar("mkdir", path, ""); err != nil {
+		return err
+	}
 
-	// recorrer components
-	for idx, comp := range components {
-		if comp == "" {
-			continue
-		}
-		found := false
+	_, err := crearDirectorios(c.v, componentes, crearPadres, c.uid, c.gid)
+	return err
+}
 
-		// buscar en los bloques del inodo actual
-		for b := 0; b < len(currentInode.I_block); b++ {
-			blk := currentInode.I_block[b]
-			if blk == int64(-1) {
-				continue
+// verificarRutaNueva revisa, sin modificar el disco, que se pueda crear la
+// ruta: las carpetas existentes deben ser directorios, sin crearPadres solo
+// puede faltar el último componente, que no debe existir, y debe haber
+// inodos y bloques libres suficientes. tamaño es el del archivo a crear en
+// el último componente, o -1 si es una carpeta.
+func verificarRutaNueva(v *FS.Volumen, componentes []string, crearPadres bool, tamaño int64) error {
+	actual := int64(0)
+	inodos, bloques := int64(0), int64(0)
+
+	for i, comp := range componentes {
+		ultimo := i == len(componentes)-1
+		if inodos == 0 {
+			inodo, err := v.ReadInode(actual)
+			if err != nil {
+				return err
 			}
-			// calcular offset bloque en disco
-			posBloque := blockStart + int64(tamBloque)*blk
-			var bc Structs.BloquesCarpetas
-			file.Seek(posBloque, 0)
-			tmpb := make([]byte, tamBloque)
-			if _, err := file.Read(tmpb); err != nil {
-				return Utils.Error("MKDIR", "Error al leer bloque de carpetas: "+err.Error())
+			if inodo.I_type != FS.TipoCarpeta {
+				return fmt.Errorf("'%s' no es un directorio", componentes[i-1])
 			}
-			if err := binary.Read(bytes.NewBuffer(tmpb), binary.BigEndian, &bc); err != nil {
-				return Utils.Error("MKDIR", "Error al decodificar bloque de carpetas: "+err.Error())
+			siguiente, err := v.BuscarEnDirectorio(inodo, comp)
+			if err != nil {
+				return err
 			}
-
-			// revisar entradas
-			for e := 0; e < len(bc.B_content); e++ {
-				name := strings.Trim(string(bc.B_content[e].B_name[:]), "\x00")
-				if name == comp && bc.B_content[e].B_inodo != int64(-1) {
-					// avanzar al inodo apuntado
-					inodoIdx := bc.B_content[e].B_inodo
-					// leer inodo destino
-					posInodo := inodoStart + int64(tamInodo)*inodoIdx
-					file.Seek(posInodo, 0)
-					dataIn := make([]byte, tamInodo)
-					if _, err := file.Read(dataIn); err != nil {
-						return Utils.Error("MKDIR", "Error al leer inodo destino: "+err.Error())
-					}
-					var iTmp Structs.Inodos
-					if err := binary.Read(bytes.NewBuffer(dataIn), binary.BigEndian, &iTmp); err != nil {
-						return Utils.Error("MKDIR", "Error al decodificar inodo destino: "+err.Error())
-					}
-					currentInode = iTmp
-					currentInodeOffset = posInodo
-					found = true
-					break
+			if siguiente != -1 {
+				if ultimo {
+					return fmt.Errorf("ya existe '%s'", "/"+strings.Join(componentes, "/"))
 				}
+				actual = siguiente
+				continue
 			}
-			if found {
-				break
+			// Primer componente que falta: puede requerir un bloque en su padre
+			n, err := v.BloquesParaEntrada(inodo)
+			if err != nil {
+				return err
 			}
+			bloques += n
 		}
 
-		if !found {
-			// no existe componente comp
-			if !crearPadres && idx != len(components)-1 {
-				// padre inexistente
-				return Utils.Error("MKDIR", "No existe el directorio padre: "+comp)
+		if !crearPadres && !ultimo {
+			return fmt.Errorf("No existe el directorio padre: %s", comp)
+		}
+		if err := FS.ValidarNombre(comp); err != nil {
+			return err
+		}
+		inodos++
+		if ultimo && tamaño >= 0 {
+			datos := (int(tamaño) + FS.TamContenidoBloque - 1) / FS.TamContenidoBloque
+			if datos > FS.MaxBloquesInodo {
+				return fmt.Errorf("se requieren %d bloques y un inodo admite como máximo %d", datos, FS.MaxBloquesInodo)
 			}
-			// crear el directorio comp dentro del currentInode
-			placed := false
-			for b := 0; b < len(currentInode.I_block) && !placed; b++ {
-				blk := currentInode.I_block[b]
-				if blk == int64(-1) {
-					// asignar nuevo bloque al padre
-					super.S_first_blo++
-					nBloque := super.S_first_blo // int64
-					currentInode.I_block[b] = nBloque
-					// marcar bitmap block (offset en bytes: bmBloquesStart + nBloque)
-					file.Seek(bmBloquesStart+int64(nBloque), 0)
-					file.Write([]byte{'1'})
-
-					// crear bloque vacío y escribirlo (inicializar entradas como libres)
-					var newBlock Structs.BloquesCarpetas
-					for i := 0; i < len(newBlock.B_content); i++ {
-						newBlock.B_content[i].B_inodo = int64(-1)
-						for j := 0; j < len(newBlock.B_content[i].B_name); j++ {
-							newBlock.B_content[i].B_name[j] = 0
-						}
-					}
-					copy(newBlock.B_content[0].B_name[:], ".")
-					newBlock.B_content[0].B_inodo = int64((currentInodeOffset - inodoStart) / int64(tamInodo))
-					copy(newBlock.B_content[1].B_name[:], "..")
-					newBlock.B_content[1].B_inodo = int64((currentInodeOffset - inodoStart) / int64(tamInodo))
-
-					// escribir block en disco
-					posNewBlock := blockStart + int64(tamBloque)*nBloque
-					file.Seek(posNewBlock, 0)
-					var bufNb bytes.Buffer
-					if err := binary.Write(&bufNb, binary.BigEndian, &newBlock); err != nil {
-						return Utils.Error("MKDIR", "Error al serializar nuevo bloque: "+err.Error())
-					}
-					if _, err := file.Write(bufNb.Bytes()); err != nil {
-						return Utils.Error("MKDIR", "Error al escribir nuevo bloque: "+err.Error())
-					}
-
-					// persistir inodo padre actualizado (I_block modificado)
-					file.Seek(currentInodeOffset, 0)
-					var bufPad bytes.Buffer
-					if err := binary.Write(&bufPad, binary.BigEndian, &currentInode); err != nil {
-						return Utils.Error("MKDIR", "Error al serializar inodo padre: "+err.Error())
-					}
-					if _, err := file.Write(bufPad.Bytes()); err != nil {
-						return Utils.Error("MKDIR", "Error al escribir inodo padre: "+err.Error())
-					}
-
-					// actualizar superbloque en disco tras asignación de bloque
-					file.Seek(particion.Part_start, 0)
-					var bufS1 bytes.Buffer
-					if err := binary.Write(&bufS1, binary.BigEndian, &super); err != nil {
-						return Utils.Error("MKDIR", "Error al serializar superbloque: "+err.Error())
-					}
-					if _, err := file.Write(bufS1.Bytes()); err != nil {
-						return Utils.Error("MKDIR", "Error al escribir superbloque: "+err.Error())
-					}
-				}
-
-				// leer de nuevo el bloque (sea asignado o existente) y buscar entrada libre
-				blk2 := currentInode.I_block[b]
-				if blk2 == int64(-1) {
-					continue
-				}
-				posBloque2 := blockStart + int64(tamBloque)*blk2
-				file.Seek(posBloque2, 0)
-				var bc2 Structs.BloquesCarpetas
-				tmpb2 := make([]byte, tamBloque)
-				if _, err := file.Read(tmpb2); err != nil {
-					return Utils.Error("MKDIR", "Error al leer bloque para inserción: "+err.Error())
-				}
-				if err := binary.Read(bytes.NewBuffer(tmpb2), binary.BigEndian, &bc2); err != nil {
-					return Utils.Error("MKDIR", "Error al decodificar bloque para inserción: "+err.Error())
-				}
-
-				for e := 0; e < len(bc2.B_content); e++ {
-					if bc2.B_content[e].B_inodo == int64(-1) {
-						// asignar nuevo inodo para el directorio
-						super.S_firts_ino++
-						nInodo := super.S_firts_ino // int64
-						// marcar bitmap inodo
-						file.Seek(bmInodosStart+int64(nInodo), 0)
-						file.Write([]byte{'1'})
-
-						// crear inodo nuevo
-						var newInodo Structs.Inodos
-						newInodo.I_uid = int64(sesion.Uid)
-						newInodo.I_gid = int64(sesion.Gid)
-						newInodo.I_size = int64(tamBloque)
-						newInodo.I_type = 0 // directorio
-						newInodo.I_perm = 664
-						// asignar bloque para el nuevo inodo
-						super.S_first_blo++
-						nBloque2 := super.S_first_blo // int64
-						// inicializar blocks del inodo y asignar el primero
-						for ib := 0; ib < len(newInodo.I_block); ib++ {
-							newInodo.I_block[ib] = int64(-1)
-						}
-						newInodo.I_block[0] = nBloque2
-
-						// escribir inodo en su posición
-						posNewInodo := inodoStart + int64(tamInodo)*nInodo
-						file.Seek(posNewInodo, 0)
-						var bufNi bytes.Buffer
-						if err := binary.Write(&bufNi, binary.BigEndian, &newInodo); err != nil {
-							return Utils.Error("MKDIR", "Error al serializar nuevo inodo: "+err.Error())
-						}
-						if _, err := file.Write(bufNi.Bytes()); err != nil {
-							return Utils.Error("MKDIR", "Error al escribir nuevo inodo: "+err.Error())
-						}
+			bloques += int64(FS.BloquesTotales(datos))
+		} else {
+			bloques++
+		}
+	}
 
-						// crear bloque de la carpeta nueva (con . y ..) e inicializar entradas libres
-						var newBlock Structs.BloquesCarpetas
-						for i := 0; i < len(newBlock.B_content); i++ {
-							newBlock.B_content[i].B_inodo = int64(-1)
-							for j := 0; j < len(newBlock.B_content[i].B_name); j++ {
-								newBlock.B_content[i].B_name[j] = 0
-							}
-						}
-						copy(newBlock.B_content[0].B_name[:], ".")
-						newBlock.B_content[0].B_inodo = nInodo
-						copy(newBlock.B_content[1].B_name[:], "..")
-						padreIdx := int64((currentInodeOffset - inodoStart) / int64(tamInodo))
-						newBlock.B_content[1].B_inodo = padreIdx
+	if inodos > v.Super.S_free_inodes_count {
+		return fmt.Errorf("no hay inodos libres suficientes (se necesitan %d, hay %d)", inodos, v.Super.S_free_inodes_count)
+	}
+	if bloques > v.Super.S_free_blocks_count {
+		return fmt.Errorf("no hay bloques libres suficientes (se necesitan %d, hay %d)", bloques, v.Super.S_free_blocks_count)
+	}
+	return nil
+}
 
-						// escribir nuevo bloque en disco
-						posBloqueNew := blockStart + int64(tamBloque)*nBloque2
-						file.Seek(posBloqueNew, 0)
-						var bufBn bytes.Buffer
-						if err := binary.Write(&bufBn, binary.BigEndian, &newBlock); err != nil {
-							return Utils.Error("MKDIR", "Error al serializar bloque nuevo: "+err.Error())
-						}
-						if _, err := file.Write(bufBn.Bytes()); err != nil {
-							return Utils.Error("MKDIR", "Error al escribir bloque nuevo: "+err.Error())
-						}
+// crearDirectorios recorre los componentes desde la raíz creando las carpetas
+// que falten. Sin crearPadres solo puede faltar el último componente.
+// Retorna el número de inodo de la última carpeta.
+func crearDirectorios(v *FS.Volumen, componentes []string, crearPadres bool, uid, gid int64) (int64, error) {
+	actual := int64(0)
 
-						// actualizar la entrada del bloque padre
-						bc2.B_content[e].B_inodo = nInodo
-						copy(bc2.B_content[e].B_name[:], comp)
-						// escribir el bloque padre actualizado
-						file.Seek(posBloque2, 0)
-						var bufBp bytes.Buffer
-						if err := binary.Write(&bufBp, binary.BigEndian, &bc2); err != nil {
-							return Utils.Error("MKDIR", "Error al serializar bloque padre: "+err.Error())
-						}
-						if _, err := file.Write(bufBp.Bytes()); err != nil {
-							return Utils.Error("MKDIR", "Error al escribir bloque padre: "+err.Error())
-						}
+	for i, comp := range componentes {
+		inodo, err := v.ReadInode(actual)
+		if err != nil {
+			return -1, err
+		}
+		if inodo.I_type != FS.TipoCarpeta {
+			return -1, fmt.Errorf("'%s' no es un directorio", componentes[i-1])
+		}
 
-						// escribir superbloque actualizado (contadores)
-						file.Seek(particion.Part_start, 0)
-						var bufS bytes.Buffer
-						if err := binary.Write(&bufS, binary.BigEndian, &super); err != nil {
-							return Utils.Error("MKDIR", "Error al serializar superbloque: "+err.Error())
-						}
-						if _, err := file.Write(bufS.Bytes()); err != nil {
-							return Utils.Error("MKDIR", "Error al escribir superbloque: "+err.Error())
-						}
-						file.Sync()
+		siguiente, err := v.BuscarEnDirectorio(inodo, comp)
+		if err != nil {
+			return -1, err
+		}
 
-						placed = true
-						break
-					}
-				}
-			}
-			if !placed {
-				return Utils.Error("MKDIR", "No hay espacio para crear el directorio: "+comp)
+		if siguiente == -1 {
+			if !crearPadres && i != len(componentes)-1 {
+				return -1, fmt.Errorf("No existe el directorio padre: %s", comp)
 			}
-
-			// re-lectura del inodo padre para continuar
-			file.Seek(currentInodeOffset, 0)
-			dtmp := make([]byte, tamInodo)
-			if _, err := file.Read(dtmp); err == nil {
-				var itmp Structs.Inodos
-				_ = binary.Read(bytes.NewBuffer(dtmp), binary.BigEndian, &itmp)
-				currentInode = itmp
+			siguiente, err = v.CrearDirectorio(actual, comp, uid, gid)
+			if err != nil {
+				return -1, fmt.Errorf("No se pudo crear el directorio '%s': %v", comp, err)
 			}
+			fmt.Printf("🔧 DEBUG: Directorio '%s' creado en inodo %d\n", comp, siguiente)
 		}
-	} // fin recorrido components
+		actual = siguiente
+	}
 
-	return Utils.Mensaje("MKDIR", fmt.Sprintf("Directorio '%s' creado correctamente", path))
+	final, err := v.ReadInode(actual)
+	if err != nil {
+		return -1, err
+	}
+	if final.I_type != FS.TipoCarpeta {
+		return -1, fmt.Errorf("'%s' no es un directorio", componentes[len(componentes)-1])
+	}
+	return actual, nil
 }
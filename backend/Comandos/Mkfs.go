@@ -1,14 +1,13 @@
 package Comandos
 
 import (
-	"encoding/binary"
+	"bytes"
 	"fmt"
 	"math"
-	"os"
 	"strings"
 	"time"
-	"unsafe"
 
+	"godisk-backend/FS"
 	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
@@ -74,23 +73,32 @@ func mkfs(id, tipo, fs string) string {
 	}
 
 	if fs == "2fs" {
-		return formatearEXT2(*particion, path, tipo)
+		return formatearParticion(*particion, path, tipo, FS.SistemaEXT2)
 	} else if fs == "3fs" {
-		return Utils.Error("MKFS", "EXT3 no está implementado en esta versión")
+		return formatearParticion(*particion, path, tipo, FS.SistemaEXT3)
 	} else {
 		return Utils.Error("MKFS", "Sistema de archivos no válido")
 	}
 }
 
-// formatearEXT2 formatea una partición con EXT2
-func formatearEXT2(particion Structs.Particion, path, tipo string) string {
+// formatearParticion formatea una partición con EXT2 o EXT3. En EXT3 se
+// reserva el área de journaling (n entradas, una por inodo) entre el
+// superbloque y los bitmaps; ese es el límite de entradas hasta el siguiente
+// FSCK -checkpoint.
+func formatearParticion(particion Structs.Particion, path, tipo string, sistema int64) string {
 	// Calcular el número de inodos y bloques
-	// n = (partition.size - sizeof(superblock)) / (4 + sizeof(inode) + 3*sizeof(block))
-	superBloqueSize := int64(unsafe.Sizeof(Structs.SuperBloque{}))
-	inodoSize := int64(unsafe.Sizeof(Structs.Inodos{}))
-	bloqueSize := int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
+	// EXT2: n = (partition.size - sizeof(superblock)) / (4 + sizeof(inode) + 3*sizeof(block))
+	// EXT3: n = (partition.size - sizeof(superblock)) / (4 + sizeof(journal) + sizeof(inode) + 3*sizeof(block))
+	// Se usan los tamaños serializados del codec, que son los que ocupan en disco
+	superBloqueSize := int64(Structs.TamSuperBloque)
+	inodoSize := int64(Structs.TamInodo)
+	bloqueSize := int64(Structs.TamBloqueCarpetas)
+	journalSize := int64(0)
+	if sistema == FS.SistemaEXT3 {
+		journalSize = Structs.TamJournal
+	}
 
-	n := math.Floor(float64(particion.Part_size-superBloqueSize) / float64(4+inodoSize+3*bloqueSize))
+	n := math.Floor(float64(particion.Part_size-superBloqueSize) / float64(4+journalSize+inodoSize+3*bloqueSize))
 	numInodos := int64(n)
 	numBloques := int64(3 * n)
 
@@ -98,7 +106,7 @@ func formatearEXT2(particion Structs.Particion, path, tipo string) string {
 
 	// Crear SuperBloque
 	spr := Structs.NewSuperBloque()
-	spr.S_filesystem_type = 2 // EXT2
+	spr.S_filesystem_type = sistema
 	spr.S_inodes_count = numInodos
 	spr.S_blocks_count = numBloques
 	spr.S_free_inodes_count = numInodos
@@ -112,67 +120,45 @@ func formatearEXT2(particion Structs.Particion, path, tipo string) string {
 	spr.S_mnt_count = 1
 
 	// Calcular posiciones de las estructuras
-	spr.S_bm_inode_start = particion.Part_start + superBloqueSize
+	if sistema == FS.SistemaEXT3 {
+		spr.S_journal_start = particion.Part_start + superBloqueSize
+	}
+	spr.S_bm_inode_start = particion.Part_start + superBloqueSize + numInodos*journalSize
 	spr.S_bm_block_start = spr.S_bm_inode_start + numInodos
 	spr.S_inode_start = spr.S_bm_block_start + numBloques
 	spr.S_block_start = spr.S_inode_start + (numInodos * inodoSize)
 	spr.S_firts_ino = 0
 	spr.S_first_blo = 0
 
-	fmt.Printf("🔧 DEBUG: Posiciones calculadas - BMI: %d, BMB: %d, Inodos: %d, Bloques: %d\n",
-		spr.S_bm_inode_start, spr.S_bm_block_start, spr.S_inode_start, spr.S_block_start)
+	fmt.Printf("🔧 DEBUG: Posiciones calculadas - Journal: %d, BMI: %d, BMB: %d, Inodos: %d, Bloques: %d\n",
+		spr.S_journal_start, spr.S_bm_inode_start, spr.S_bm_block_start, spr.S_inode_start, spr.S_block_start)
 
-	// Abrir archivo para escritura
-	file, err := os.OpenFile(path, os.O_RDWR, 0666)
+	// Abrir el volumen y escribir el SuperBloque
+	v, err := FS.Crear(path, particion, spr)
 	if err != nil {
-		return Utils.Error("MKFS", "No se pudo abrir el disco: "+err.Error())
-	}
-	defer file.Close()
-
-	// Escribir SuperBloque
-	file.Seek(particion.Part_start, 0)
-	if err := binary.Write(file, binary.BigEndian, spr); err != nil {
-		return Utils.Error("MKFS", "Error al escribir el superbloque")
-	}
-
-	// Inicializar bitmap de inodos (todos en '0')
-	file.Seek(spr.S_bm_inode_start, 0)
-	for i := 0; i < int(numInodos); i++ {
-		file.Write([]byte{'0'})
-	}
-
-	// Inicializar bitmap de bloques (todos en '0')
-	file.Seek(spr.S_bm_block_start, 0)
-	for i := 0; i < int(numBloques); i++ {
-		file.Write([]byte{'0'})
+		return Utils.Error("MKFS", err.Error())
 	}
+	defer v.Cerrar()
 
-	// Inicializar inodos vacíos
-	inodoVacio := Structs.NewInodos()
-	file.Seek(spr.S_inode_start, 0)
-	for i := 0; i < int(numInodos); i++ {
-		if err := binary.Write(file, binary.BigEndian, inodoVacio); err != nil {
-			return Utils.Error("MKFS", "Error al escribir inodos")
+	// Inicializar el área de journaling con entradas libres
+	if sistema == FS.SistemaEXT3 {
+		if err := v.IniciarJournal(); err != nil {
+			return Utils.Error("MKFS", err.Error())
 		}
 	}
 
-	// Inicializar bloques vacíos
-	bloqueVacio := Structs.NewBloquesCarpetas()
-	file.Seek(spr.S_block_start, 0)
-	for i := 0; i < int(numInodos); i++ { // Solo n bloques de carpetas
-		if err := binary.Write(file, binary.BigEndian, bloqueVacio); err != nil {
-			return Utils.Error("MKFS", "Error al escribir bloques")
-		}
+	if err := inicializarAreas(v); err != nil {
+		return Utils.Error("MKFS", err.Error())
 	}
 
 	if tipo == "full" {
 		// Crear estructura inicial del sistema de archivos
-		if err := crearEstructuraInicial(file, spr, particion); err != nil {
+		if err := crearEstructuraInicial(v); err != nil {
 			return Utils.Error("MKFS", "Error al crear estructura inicial: "+err.Error())
 		}
 
 		// AGREGAR VERIFICACIÓN COMPLETA:
-		verificarEstructuras(file, spr, particion, tipo)
+		verificarEstructuras(v)
 	}
 
 	// Obtener nombre de la partición
@@ -185,191 +171,190 @@ func formatearEXT2(particion Structs.Particion, path, tipo string) string {
 		}
 	}
 
-	return Utils.Mensaje("MKFS", fmt.Sprintf("Partición '%s' formateada correctamente con EXT2", nombreParticion))
+	return Utils.Mensaje("MKFS", fmt.Sprintf("Partición '%s' formateada correctamente con EXT%d", nombreParticion, sistema))
+}
+
+// inicializarAreas deja libres los bitmaps, vacía la tabla de inodos y pone
+// en cero toda el área de bloques. Lo usan MKFS y RECOVERY antes de crear la
+// estructura inicial.
+func inicializarAreas(v *FS.Volumen) error {
+	// Inicializar bitmaps (todos en '0')
+	if err := v.EscribirBitmapInodos(bytes.Repeat([]byte{FS.BitmapLibre}, int(v.Super.S_inodes_count))); err != nil {
+		return err
+	}
+	if err := v.EscribirBitmapBloques(bytes.Repeat([]byte{FS.BitmapLibre}, int(v.Super.S_blocks_count))); err != nil {
+		return err
+	}
+
+	// Inicializar inodos vacíos
+	inodoVacio := Structs.NewInodos()
+	for i := int64(0); i < v.Super.S_inodes_count; i++ {
+		if err := v.WriteInode(i, inodoVacio); err != nil {
+			return err
+		}
+	}
+
+	// Inicializar todos los bloques en cero (S_block_size bytes cada uno)
+	bloqueVacio := make([]byte, v.Super.S_block_size)
+	for i := int64(0); i < v.Super.S_blocks_count; i++ {
+		if err := v.WriteBlock(i, bloqueVacio); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// verificarEstructuras muestra las posiciones reales y verifica el contenido
-func verificarEstructuras(file *os.File, spr Structs.SuperBloque, particion Structs.Particion, tipo string) {
+// verificarEstructuras muestra las posiciones de las estructuras y vuelve a
+// leer el contenido creado por MKFS
+func verificarEstructuras(v *FS.Volumen) {
+	spr := v.Super
 	fmt.Println("\n🔍 POSICIONES REALES DE LAS ESTRUCTURAS:")
 	fmt.Println("═══════════════════════════════════════════")
 
-	fmt.Printf("Partición inicia en: %d (0x%x)\n", particion.Part_start, particion.Part_start)
-	fmt.Printf("SuperBloque en: %d (0x%x)\n", particion.Part_start, particion.Part_start)
+	fmt.Printf("Partición inicia en: %d (0x%x)\n", v.Particion.Part_start, v.Particion.Part_start)
+	fmt.Printf("SuperBloque en: %d (0x%x)\n", v.Particion.Part_start, v.Particion.Part_start)
+	if spr.S_journal_start != 0 {
+		fmt.Printf("Journal en: %d (0x%x)\n", spr.S_journal_start, spr.S_journal_start)
+	}
 	fmt.Printf("Bitmap inodos en: %d (0x%x)\n", spr.S_bm_inode_start, spr.S_bm_inode_start)
 	fmt.Printf("Bitmap bloques en: %d (0x%x)\n", spr.S_bm_block_start, spr.S_bm_block_start)
 	fmt.Printf("Tabla inodos en: %d (0x%x)\n", spr.S_inode_start, spr.S_inode_start)
 	fmt.Printf("Área bloques en: %d (0x%x)\n", spr.S_block_start, spr.S_block_start)
 
-	// Calcular posición del bloque 1 (users.txt)
-	tamañoBloque := int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	posicionBloque1 := spr.S_block_start + tamañoBloque
-	fmt.Printf("Bloque users.txt en: %d (0x%x)\n", posicionBloque1, posicionBloque1)
-
+	fmt.Println("\n🔍 VERIFICANDO CONTENIDO CREADO:")
 	fmt.Println("═══════════════════════════════════════════")
-	fmt.Println("🔧 COMANDOS PARA VERIFICAR:")
-	fmt.Printf("xxd -s +%d -l 100 Disco2.mia  # SuperBloque\n", particion.Part_start)
-	fmt.Printf("xxd -s +%d -l 20 Disco2.mia   # Bitmap inodos\n", spr.S_bm_inode_start)
-	fmt.Printf("xxd -s +%d -l 20 Disco2.mia   # Bitmap bloques\n", spr.S_bm_block_start)
-	fmt.Printf("xxd -s +%d -l 100 Disco2.mia  # Tabla inodos\n", spr.S_inode_start)
-	fmt.Printf("xxd -s +%d -l 100 Disco2.mia  # Directorio raíz\n", spr.S_block_start)
-	fmt.Printf("xxd -s +%d -l 50 Disco2.mia   # Archivo users.txt\n", posicionBloque1)
 
-	if tipo == "full" {
-		fmt.Println("\n🔍 VERIFICANDO CONTENIDO CREADO:")
-		fmt.Println("═══════════════════════════════════════════")
-
-		// 1. Verificar SuperBloque
-		file.Seek(particion.Part_start, 0)
-		var sprLeido Structs.SuperBloque
-		if err := binary.Read(file, binary.BigEndian, &sprLeido); err != nil {
-			fmt.Printf("❌ Error leyendo superbloque: %v\n", err)
-		} else {
-			fmt.Printf("✅ SuperBloque leído correctamente:\n")
-			fmt.Printf("   - Tipo FS: %d (debería ser 2)\n", sprLeido.S_filesystem_type)
-			fmt.Printf("   - Inodos totales: %d\n", sprLeido.S_inodes_count)
-			fmt.Printf("   - Bloques totales: %d\n", sprLeido.S_blocks_count)
-			fmt.Printf("   - Inodos libres: %d\n", sprLeido.S_free_inodes_count)
-			fmt.Printf("   - Bloques libres: %d\n", sprLeido.S_free_blocks_count)
-		}
+	// 1. Verificar SuperBloque
+	if sprLeido, err := v.ReadSuperblock(); err != nil {
+		fmt.Printf("❌ Error leyendo superbloque: %v\n", err)
+	} else {
+		fmt.Printf("✅ SuperBloque leído correctamente:\n")
+		fmt.Printf("   - Tipo FS: %d\n", sprLeido.S_filesystem_type)
+		fmt.Printf("   - Inodos totales: %d\n", sprLeido.S_inodes_count)
+		fmt.Printf("   - Bloques totales: %d\n", sprLeido.S_blocks_count)
+		fmt.Printf("   - Inodos libres: %d\n", sprLeido.S_free_inodes_count)
+		fmt.Printf("   - Bloques libres: %d\n", sprLeido.S_free_blocks_count)
+	}
 
-		// 2. Verificar bitmap de inodos
-		file.Seek(spr.S_bm_inode_start, 0)
-		bitmapInodos := make([]byte, 10) // Leer primeros 10 bytes
-		file.Read(bitmapInodos)
-		fmt.Printf("✅ Bitmap inodos (primeros 10): %s\n", string(bitmapInodos))
-
-		// 3. Verificar bitmap de bloques
-		file.Seek(spr.S_bm_block_start, 0)
-		bitmapBloques := make([]byte, 10) // Leer primeros 10 bytes
-		file.Read(bitmapBloques)
-		fmt.Printf("✅ Bitmap bloques (primeros 10): %s\n", string(bitmapBloques))
-
-		// 4. Verificar inodo raíz
-		file.Seek(spr.S_inode_start, 0)
-		var inodoRaiz Structs.Inodos
-		if err := binary.Read(file, binary.BigEndian, &inodoRaiz); err != nil {
-			fmt.Printf("❌ Error leyendo inodo raíz: %v\n", err)
-		} else {
-			fmt.Printf("✅ Inodo raíz leído:\n")
-			fmt.Printf("   - Tipo: %d (0=directorio)\n", inodoRaiz.I_type)
-			fmt.Printf("   - Tamaño: %d bytes\n", inodoRaiz.I_size)
-			fmt.Printf("   - Bloque[0]: %d\n", inodoRaiz.I_block[0])
-		}
+	// 2. Verificar bitmaps (primeros 10 bytes)
+	if bitmapInodos, err := v.LeerBitmapInodos(); err == nil {
+		fmt.Printf("✅ Bitmap inodos (primeros 10): %s\n", string(bitmapInodos[:min(10, len(bitmapInodos))]))
+	}
+	if bitmapBloques, err := v.LeerBitmapBloques(); err == nil {
+		fmt.Printf("✅ Bitmap bloques (primeros 10): %s\n", string(bitmapBloques[:min(10, len(bitmapBloques))]))
+	}
 
-		// 5. Verificar inodo users.txt
-		var inodoUsers Structs.Inodos
-		if err := binary.Read(file, binary.BigEndian, &inodoUsers); err != nil {
-			fmt.Printf("❌ Error leyendo inodo users.txt: %v\n", err)
-		} else {
-			fmt.Printf("✅ Inodo users.txt leído:\n")
-			fmt.Printf("   - Tipo: %d (1=archivo)\n", inodoUsers.I_type)
-			fmt.Printf("   - Tamaño: %d bytes\n", inodoUsers.I_size)
-			fmt.Printf("   - Bloque[0]: %d\n", inodoUsers.I_block[0])
-		}
+	// 3. Verificar inodo raíz
+	if inodoRaiz, err := v.ReadInode(0); err != nil {
+		fmt.Printf("❌ Error leyendo inodo raíz: %v\n", err)
+	} else {
+		fmt.Printf("✅ Inodo raíz leído:\n")
+		fmt.Printf("   - Tipo: %d (0=directorio)\n", inodoRaiz.I_type)
+		fmt.Printf("   - Tamaño: %d bytes\n", inodoRaiz.I_size)
+		fmt.Printf("   - Bloque[0]: %d\n", inodoRaiz.I_block[0])
+	}
 
-		// 6. Verificar contenido del bloque del directorio raíz
-		file.Seek(spr.S_block_start, 0)
-		var bloqueRaiz Structs.BloquesCarpetas
-		if err := binary.Read(file, binary.BigEndian, &bloqueRaiz); err != nil {
-			fmt.Printf("❌ Error leyendo bloque directorio raíz: %v\n", err)
-		} else {
-			nombre2 := ""
-			for _, b := range bloqueRaiz.B_content[2].B_name {
-				if b != 0 {
-					nombre2 += string(b)
-				} else {
-					break
-				}
-			}
-			fmt.Printf("✅ Directorio raíz leído:\n")
-			fmt.Printf("   - Entrada[2]: '%s' -> inodo %d\n", nombre2, bloqueRaiz.B_content[2].B_inodo)
-		}
+	// 4. Verificar inodo users.txt y su contenido
+	if inodoUsers, err := v.ReadInode(1); err != nil {
+		fmt.Printf("❌ Error leyendo inodo users.txt: %v\n", err)
+	} else {
+		fmt.Printf("✅ Inodo users.txt leído:\n")
+		fmt.Printf("   - Tipo: %d (1=archivo)\n", inodoUsers.I_type)
+		fmt.Printf("   - Tamaño: %d bytes\n", inodoUsers.I_size)
+		fmt.Printf("   - Bloque[0]: %d\n", inodoUsers.I_block[0])
 
-		// 7. Verificar contenido del archivo users.txt
-		file.Seek(posicionBloque1, 0)
-		var bloqueUsers Structs.BloquesArchivos
-		if err := binary.Read(file, binary.BigEndian, &bloqueUsers); err != nil {
-			fmt.Printf("❌ Error leyendo bloque users.txt: %v\n", err)
+		if contenido, err := v.LeerArchivo(inodoUsers); err != nil {
+			fmt.Printf("❌ Error leyendo users.txt: %v\n", err)
 		} else {
-			contenido := ""
-			for _, b := range bloqueUsers.B_content {
-				if b != 0 {
-					contenido += string(b)
-				} else {
-					break
-				}
-			}
 			fmt.Printf("✅ Archivo users.txt leído:\n")
 			fmt.Printf("   - Contenido: %q\n", contenido)
 		}
 	}
 
+	// 5. Verificar contenido del bloque del directorio raíz
+	var bloqueRaiz Structs.BloquesCarpetas
+	if err := v.ReadBlock(0, &bloqueRaiz); err != nil {
+		fmt.Printf("❌ Error leyendo bloque directorio raíz: %v\n", err)
+	} else {
+		fmt.Printf("✅ Directorio raíz leído:\n")
+		fmt.Printf("   - Entrada[2]: '%s' -> inodo %d\n", FS.NombreEntrada(bloqueRaiz.B_content[2]), bloqueRaiz.B_content[2].B_inodo)
+	}
+
 	fmt.Println("═══════════════════════════════════════════")
 	fmt.Println("🎯 VERIFICACIÓN COMPLETADA")
 }
 
-// crearEstructuraInicial crea la estructura inicial del sistema de archivos
-func crearEstructuraInicial(file *os.File, spr Structs.SuperBloque, particion Structs.Particion) error {
-	fecha := time.Now().Format("2006-01-02 15:04:05")
+// crearEstructuraInicial crea la carpeta raíz (inodo 0, bloque 0) con el
+// archivo users.txt (inodo 1, bloque 1)
+func crearEstructuraInicial(v *FS.Volumen) error {
+	fecha := FS.FechaActual()
 
 	// Actualizar contadores del superbloque
 	// Reservamos inodos 0 y 1 y bloques 0 y 1
-	spr.S_free_inodes_count -= 2 // Restamos 2 inodos (raíz + users.txt)
-	spr.S_free_blocks_count -= 2 // Restamos 2 bloques (raíz + users.txt)
+	v.Super.S_free_inodes_count -= 2 // Restamos 2 inodos (raíz + users.txt)
+	v.Super.S_free_blocks_count -= 2 // Restamos 2 bloques (raíz + users.txt)
 
-	// S_firts_ino y S_first_blo deben reflejar el último índice asignado (1)
-	spr.S_firts_ino = 1
-	spr.S_first_blo = 1
+	// S_firts_ino y S_first_blo apuntan a la primera entrada libre del bitmap
+	v.Super.S_firts_ino = 2
+	v.Super.S_first_blo = 2
 
 	// Reescribir superbloque actualizado
-	file.Seek(particion.Part_start, 0)
-	if err := binary.Write(file, binary.BigEndian, spr); err != nil {
+	if err := v.WriteSuperblock(); err != nil {
 		return err
 	}
 
 	// Marcar inodos y bloques como ocupados en los bitmaps (usar '1')
-	file.Seek(spr.S_bm_inode_start, 0)
-	file.Write([]byte{'1'}) // Inodo 0 (directorio raíz)
-	file.Write([]byte{'1'}) // Inodo 1 (archivo users.txt)
+	bitmapInodos, err := v.LeerBitmapInodos()
+	if err != nil {
+		return err
+	}
+	bitmapInodos[0] = FS.BitmapOcupado // Inodo 0 (directorio raíz)
+	bitmapInodos[1] = FS.BitmapOcupado // Inodo 1 (archivo users.txt)
+	if err := v.EscribirBitmapInodos(bitmapInodos); err != nil {
+		return err
+	}
 
-	file.Seek(spr.S_bm_block_start, 0)
-	file.Write([]byte{'1'}) // Bloque 0 (directorio raíz)
-	file.Write([]byte{'1'}) // Bloque 1 (archivo users.txt)
+	bitmapBloques, err := v.LeerBitmapBloques()
+	if err != nil {
+		return err
+	}
+	bitmapBloques[0] = FS.BitmapOcupado // Bloque 0 (directorio raíz)
+	bitmapBloques[1] = FS.BitmapOcupado // Bloque 1 (archivo users.txt)
+	if err := v.EscribirBitmapBloques(bitmapBloques); err != nil {
+		return err
+	}
 
-	// Crear contenido del archivo users.txt con la estructura correcta
+	// Crear contenido del archivo users.txt con la estructura correcta. La
+	// raíz y users.txt pertenecen a root: usuario 1 del grupo 1.
+	const uidRoot, gidRoot = 1, 1
 	inodoUsersData := "1,G,root\n1,U,root,root,123\n"
 	fmt.Printf("🔧 DEBUG: Creando users.txt con contenido: %q\n", inodoUsersData)
 
 	// Crear inodo del directorio raíz
 	inodoRaiz := Structs.NewInodos()
-	inodoRaiz.I_uid = 0
-	inodoRaiz.I_gid = 0
-	inodoRaiz.I_size = int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	copy(inodoRaiz.I_atime[:], fecha)
-	copy(inodoRaiz.I_ctime[:], fecha)
-	copy(inodoRaiz.I_mtime[:], fecha)
-	inodoRaiz.I_type = 0 // Directorio
+	inodoRaiz.I_uid = uidRoot
+	inodoRaiz.I_gid = gidRoot
+	inodoRaiz.I_size = v.Super.S_block_size
+	inodoRaiz.I_atime, inodoRaiz.I_ctime, inodoRaiz.I_mtime = fecha, fecha, fecha
+	inodoRaiz.I_type = FS.TipoCarpeta
 	inodoRaiz.I_perm = 664
 	inodoRaiz.I_block[0] = 0 // Apunta al bloque 0
 
 	// Crear inodo del archivo users.txt
 	inodoUsers := Structs.NewInodos()
-	inodoUsers.I_uid = 0
-	inodoUsers.I_gid = 0
+	inodoUsers.I_uid = uidRoot
+	inodoUsers.I_gid = gidRoot
 	inodoUsers.I_size = int64(len(inodoUsersData))
-	copy(inodoUsers.I_atime[:], fecha)
-	copy(inodoUsers.I_ctime[:], fecha)
-	copy(inodoUsers.I_mtime[:], fecha)
-	inodoUsers.I_type = 1 // Archivo
+	inodoUsers.I_atime, inodoUsers.I_ctime, inodoUsers.I_mtime = fecha, fecha, fecha
+	inodoUsers.I_type = FS.TipoArchivo
 	inodoUsers.I_perm = 664
 	inodoUsers.I_block[0] = 1 // Apunta al bloque 1
 
 	// Escribir inodos
-	file.Seek(spr.S_inode_start, 0)
-	if err := binary.Write(file, binary.BigEndian, inodoRaiz); err != nil {
+	if err := v.WriteInode(0, inodoRaiz); err != nil {
 		return err
 	}
-	if err := binary.Write(file, binary.BigEndian, inodoUsers); err != nil {
+	if err := v.WriteInode(1, inodoUsers); err != nil {
 		return err
 	}
 
@@ -386,26 +371,14 @@ func crearEstructuraInicial(file *os.File, spr Structs.SuperBloque, particion St
 	var bloqueUsers Structs.BloquesArchivos
 	copy(bloqueUsers.B_content[:], inodoUsersData)
 
-	// Escribir bloque del directorio raíz (posición exacta del bloque 0)
-	file.Seek(spr.S_block_start, 0)
-	if err := binary.Write(file, binary.BigEndian, bloqueRaiz); err != nil {
+	// Escribir bloques
+	if err := v.WriteBlock(0, bloqueRaiz); err != nil {
 		return err
 	}
-
-	// Escribir bloque del archivo users.txt (posición exacta del bloque 1)
-	tamañoBloque := int64(unsafe.Sizeof(Structs.BloquesCarpetas{}))
-	posicionBloque1 := spr.S_block_start + tamañoBloque
-
-	file.Seek(posicionBloque1, 0)
-	if err := binary.Write(file, binary.BigEndian, bloqueUsers); err != nil {
+	if err := v.WriteBlock(1, bloqueUsers); err != nil {
 		return err
 	}
 
-	// Forzar escritura al disco
-	file.Sync()
-
-	fmt.Printf("✅ DEBUG: users.txt creado correctamente en posición %d\n", posicionBloque1)
-	fmt.Printf("✅ DEBUG: Contenido: %q\n", inodoUsersData)
-
+	fmt.Printf("✅ DEBUG: users.txt creado con contenido: %q\n", inodoUsersData)
 	return nil
 }
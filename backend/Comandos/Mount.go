@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"godisk-backend/FS"
 	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
@@ -187,6 +188,16 @@ func GetMount(comando string, id string, path *string) *Structs.Particion {
 	return nil
 }
 
+// abrirVolumen obtiene la partición montada con el ID y abre su sistema de archivos
+func abrirVolumen(comando string, id string) (*FS.Volumen, error) {
+	var pathDisco string
+	particion := GetMount(comando, id, &pathDisco)
+	if particion == nil {
+		return nil, fmt.Errorf("no se encontró la partición montada con el ID: %s", id)
+	}
+	return FS.Abrir(pathDisco, *particion)
+}
+
 // listaMount muestra todas las particiones montadas
 func listaMount() string {
 	fmt.Println("\n📋 LISTADO DE PARTICIONES MONTADAS")
@@ -239,7 +250,7 @@ func buscarParticionMontada(diskPath, partitionName string) string {
 			for j := 0; j < 26; j++ {
 				if DiscMont[i].Particiones[j].Estado == 1 {
 					nombreMontado := convertirAString20(DiscMont[i].Particiones[j].Nombre)
-					if nombreMontado == partitionName {
+					if Utils.Comparar(nombreMontado, partitionName) {
 						return convertirAString10(DiscMont[i].Particiones[j].Id_Particion)
 					}
 				}
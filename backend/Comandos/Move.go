@@ -0,0 +1,99 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosMOVE valida los parámetros del comando MOVE
+func ValidarDatosMOVE(tokens []string) string {
+	path, destino, err := parametrosOrigenDestino("MOVE", tokens)
+	if err != "" {
+		return err
+	}
+	return mover(path, destino)
+}
+
+// mover traslada el archivo o carpeta path a la carpeta destino cambiando solo
+// las entradas de carpeta; los bloques de datos no se reescriben
+func mover(path, destino string) string {
+	fmt.Printf("🔧 DEBUG: MOVE path='%s' destino='%s'\n", path, destino)
+
+	sesion := ObtenerSesionActiva()
+	v, err := abrirVolumen("MOVE", sesion.Id)
+	if err != nil {
+		return Utils.Error("MOVE", err.Error())
+	}
+	defer v.Cerrar()
+
+	if err := contextoSesion(v).moverRuta(path, destino); err != nil {
+		return Utils.Error("MOVE", err.Error())
+	}
+	return Utils.Mensaje("MOVE", fmt.Sprintf("'%s' movido a '%s'", path, destino))
+}
+
+// moverRuta verifica y registra el traslado de path a destino y luego enlaza
+// el elemento en el destino y lo desenlaza de su padre
+func (c *contextoFS) moverRuta(path, destino string) error {
+	componentes := FS.SepararRuta(path)
+	if len(componentes) == 0 {
+		return fmt.Errorf("no se puede mover la carpeta raíz")
+	}
+	nombre := componentes[len(componentes)-1]
+	rutaPadre := "/" + strings.Join(componentes[:len(componentes)-1], "/")
+
+	nPadre, _, err := c.v.ResolvePath(rutaPadre)
+	if err != nil {
+		return err
+	}
+	nOrigen, origen, err := c.v.ResolvePath(path)
+	if err != nil {
+		return err
+	}
+	nDestino, dirDestino, err := validarDestino(c.v, destino, nOrigen, origen, nombre)
+	if err != nil {
+		return err
+	}
+	if !c.puede(origen, permisoEscritura) {
+		return fmt.Errorf("no tiene permiso de escritura sobre %s", path)
+	}
+	if !c.puede(dirDestino, permisoEscritura) {
+		return fmt.Errorf("no tiene permiso de escritura sobre %s", destino)
+	}
+
+	// Registrar la operación en el journal antes de modificar el disco
+	if err := c.registrar("move", path, destino); err != nil {
+		return err
+	}
+
+	// Primero se enlaza en el destino (puede asignar un bloque de carpetas
+	// nuevo) y después se desenlaza del padre, para no perder el elemento
+	if err := c.v.AgregarEntrada(nDestino, nombre, nOrigen); err != nil {
+		return err
+	}
+	if err := c.v.QuitarEntrada(nPadre, nombre); err != nil {
+		// Quitar el enlace del destino para no dejar dos referencias al elemento
+		if errDeshacer := c.v.QuitarEntrada(nDestino, nombre); errDeshacer != nil {
+			return fmt.Errorf("%v; no se pudo deshacer el enlace en %s: %v", err, destino, errDeshacer)
+		}
+		return err
+	}
+	if origen.I_type == FS.TipoCarpeta {
+		if err := c.v.ApuntarEntrada(nOrigen, "..", nDestino); err != nil {
+			// Devolver la carpeta a su padre; la entrada libre que dejó
+			// QuitarEntrada se reutiliza sin asignar bloques
+			errDeshacer := c.v.AgregarEntrada(nPadre, nombre, nOrigen)
+			if errDeshacer == nil {
+				errDeshacer = c.v.QuitarEntrada(nDestino, nombre)
+			}
+			if errDeshacer != nil {
+				return fmt.Errorf("no se pudo actualizar '..': %v; no se pudo deshacer el traslado: %v", err, errDeshacer)
+			}
+			return fmt.Errorf("no se pudo actualizar '..': %v", err)
+		}
+	}
+	return nil
+}
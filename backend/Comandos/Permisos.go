@@ -0,0 +1,80 @@
+package Comandos
+
+import (
+	"godisk-backend/FS"
+	"godisk-backend/Structs"
+	"godisk-backend/Utils"
+)
+
+// Permisos de cada dígito de I_perm (I_perm guarda los tres dígitos octales
+// como número decimal, por ejemplo 664)
+const (
+	permisoLectura   int64 = 4
+	permisoEscritura int64 = 2
+)
+
+// contextoFS reúne el volumen abierto y el usuario con el que se ejecuta una
+// operación. Los comandos lo crean desde la sesión; RECOVERY lo crea con el
+// UID y GID guardados en cada entrada del journal.
+type contextoFS struct {
+	v        *FS.Volumen
+	uid, gid int64
+	root     bool
+	journal  bool // registrar las operaciones en el journal
+}
+
+// contextoSesion crea el contexto del usuario de la sesión activa
+func contextoSesion(v *FS.Volumen) *contextoFS {
+	sesion := ObtenerSesionActiva()
+	return &contextoFS{
+		v:       v,
+		uid:     int64(sesion.Uid),
+		gid:     int64(sesion.Gid),
+		root:    EsUsuarioRoot(),
+		journal: true,
+	}
+}
+
+// contextoJournal crea el contexto de una operación del journal. El usuario
+// root se reconoce por su nombre en users.txt, igual que en la sesión, y las
+// operaciones no se vuelven a registrar.
+func contextoJournal(v *FS.Volumen, uid, gid int64) (*contextoFS, error) {
+	_, _, contenido, err := leerUsuarios(v)
+	if err != nil {
+		return nil, err
+	}
+	usuarios, _ := nombresUsuarios(contenido)
+	return &contextoFS{
+		v:    v,
+		uid:  uid,
+		gid:  gid,
+		root: Utils.Comparar(usuarios[uid], "root"),
+	}, nil
+}
+
+// puede verifica si el usuario del contexto tiene el permiso indicado sobre
+// el inodo. Root tiene todos los permisos; el resto usa el dígito de
+// propietario, grupo u otros según su UID y GID.
+func (c *contextoFS) puede(inodo Structs.Inodos, permiso int64) bool {
+	if c.root {
+		return true
+	}
+
+	digito := inodo.I_perm % 10
+	switch {
+	case inodo.I_uid == c.uid:
+		digito = inodo.I_perm / 100 % 10
+	case inodo.I_gid == c.gid:
+		digito = inodo.I_perm / 10 % 10
+	}
+	return digito&permiso != 0
+}
+
+// registrar agrega la operación al journal del volumen (solo EXT3) con el
+// UID y GID del contexto
+func (c *contextoFS) registrar(operacion, ruta, contenido string) error {
+	if !c.journal {
+		return nil
+	}
+	return c.v.RegistrarJournal(operacion, ruta, contenido, c.uid, c.gid)
+}
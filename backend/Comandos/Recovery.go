@@ -0,0 +1,198 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosLOSS valida los parámetros del comando LOSS
+func ValidarDatosLOSS(tokens []string) string {
+	id := obtenerIDRecuperacion(tokens)
+	if id == "" {
+		return Utils.Error("LOSS", "El parámetro -id es obligatorio")
+	}
+	if !EsUsuarioRoot() {
+		return Utils.Error("LOSS", "Solo el usuario root puede ejecutar LOSS")
+	}
+	return loss(id)
+}
+
+// ValidarDatosRECOVERY valida los parámetros del comando RECOVERY
+func ValidarDatosRECOVERY(tokens []string) string {
+	id := obtenerIDRecuperacion(tokens)
+	if id == "" {
+		return Utils.Error("RECOVERY", "El parámetro -id es obligatorio")
+	}
+	if !EsUsuarioRoot() {
+		return Utils.Error("RECOVERY", "Solo el usuario root puede ejecutar RECOVERY")
+	}
+	return recovery(id)
+}
+
+// obtenerIDRecuperacion extrae el parámetro -id de LOSS y RECOVERY
+func obtenerIDRecuperacion(tokens []string) string {
+	id := ""
+	for _, token := range tokens {
+		tk := strings.Split(token, "=")
+		if len(tk) == 2 && Utils.Comparar(tk[0], "id") {
+			id = strings.ReplaceAll(tk[1], "\"", "")
+		}
+	}
+	return id
+}
+
+// loss simula la pérdida del sistema de archivos: pone en cero los bitmaps,
+// la tabla de inodos y el área de bloques. El superbloque y el journal se conservan.
+func loss(id string) string {
+	v, err := abrirVolumen("LOSS", id)
+	if err != nil {
+		return Utils.Error("LOSS", err.Error())
+	}
+	defer v.Cerrar()
+
+	if !v.EsEXT3() {
+		return Utils.Error("LOSS", "La partición no es EXT3")
+	}
+
+	inicio := v.Super.S_bm_inode_start
+	fin := v.Super.S_block_start + v.Super.S_blocks_count*v.Super.S_block_size
+	fmt.Printf("🔧 DEBUG: LOSS limpiando bytes %d-%d\n", inicio, fin)
+
+	ceros := make([]byte, 1024)
+	for pos := inicio; pos < fin; pos += int64(len(ceros)) {
+		n := int64(len(ceros))
+		if pos+n > fin {
+			n = fin - pos
+		}
+		if _, err := v.Archivo.WriteAt(ceros[:n], pos); err != nil {
+			return Utils.Error("LOSS", "Error al limpiar la partición: "+err.Error())
+		}
+	}
+
+	return Utils.Mensaje("LOSS", fmt.Sprintf("Se perdió la información de la partición %s (%d bytes en cero)", id, fin-inicio))
+}
+
+// recovery reconstruye el sistema de archivos desde cero y reproduce en orden
+// las operaciones registradas en el journal sobre el mismo volumen abierto,
+// cada una con el UID y GID de quien la ejecutó
+func recovery(id string) string {
+	v, err := abrirVolumen("RECOVERY", id)
+	if err != nil {
+		return Utils.Error("RECOVERY", err.Error())
+	}
+	defer v.Cerrar()
+
+	operaciones, err := v.LeerOperaciones()
+	if err != nil {
+		return Utils.Error("RECOVERY", err.Error())
+	}
+
+	// Reiniciar las estructuras y crear la raíz con users.txt
+	v.Super.S_free_inodes_count = v.Super.S_inodes_count
+	v.Super.S_free_blocks_count = v.Super.S_blocks_count
+	if err := inicializarAreas(v); err != nil {
+		return Utils.Error("RECOVERY", err.Error())
+	}
+	if err := crearEstructuraInicial(v); err != nil {
+		return Utils.Error("RECOVERY", "Error al crear estructura inicial: "+err.Error())
+	}
+
+	reproducidas := 0
+	var fallidas []string
+	for _, op := range operaciones {
+		err := reproducirOperacion(v, op)
+		fmt.Printf("🔧 DEBUG: RECOVERY #%d %s %s -> %v\n", op.Numero, op.Operacion, op.Ruta, err)
+		if err != nil {
+			fallidas = append(fallidas, fmt.Sprintf("#%d %s %s (%v)", op.Numero, op.Operacion, op.Ruta, err))
+			continue
+		}
+		reproducidas++
+	}
+
+	mensaje := fmt.Sprintf("Partición %s recuperada: %d de %d operaciones reproducidas", id, reproducidas, len(operaciones))
+	if len(fallidas) > 0 {
+		mensaje += "\n   ⚠️ No se pudieron reproducir: " + strings.Join(fallidas, ", ")
+	}
+	return Utils.Mensaje("RECOVERY", mensaje)
+}
+
+// reproducirOperacion aplica nuevamente una entrada del journal con el
+// usuario que la registró y verifica el tamaño de los archivos escritos
+func reproducirOperacion(v *FS.Volumen, op FS.OperacionJournal) error {
+	c, err := contextoJournal(v, op.Uid, op.Gid)
+	if err != nil {
+		return err
+	}
+	antes := tamañoArchivo(v, op.Ruta)
+	campos := strings.Split(op.Contenido, ",")
+
+	switch op.Operacion {
+	case "mkdir":
+		err = c.crearCarpeta(op.Ruta, true)
+	case "mkfile":
+		err = c.crearArchivo(op.Ruta, true, []byte(op.Contenido))
+	case "mkgrp":
+		err = c.crearGrupo(op.Contenido)
+	case "rmgrp":
+		err = c.eliminarGrupo(op.Contenido)
+	case "mkusr":
+		if len(campos) != 3 {
+			return fmt.Errorf("entrada mkusr inválida")
+		}
+		err = c.crearUsuario(campos[0], campos[1], campos[2])
+	case "rmusr":
+		err = c.eliminarUsuario(op.Contenido)
+	case "chgrp":
+		if len(campos) != 2 {
+			return fmt.Errorf("entrada chgrp inválida")
+		}
+		err = c.cambiarGrupo(campos[0], campos[1])
+	case "remove":
+		_, err = c.eliminarRuta(op.Ruta)
+	case "edit", "append":
+		_, err = c.editarArchivo(op.Ruta, []byte(op.Contenido), op.Operacion == "append")
+	case "rename":
+		err = c.renombrarRuta(op.Ruta, op.Contenido)
+	case "copy":
+		_, err = c.copiarRuta(op.Ruta, op.Contenido)
+	case "move":
+		err = c.moverRuta(op.Ruta, op.Contenido)
+	default:
+		return fmt.Errorf("operación desconocida en el journal: %s", op.Operacion)
+	}
+	if err != nil {
+		return err
+	}
+	return verificarTamaño(v, op, antes)
+}
+
+// tamañoArchivo retorna el tamaño del archivo en la ruta o -1 si no existe
+func tamañoArchivo(v *FS.Volumen, ruta string) int64 {
+	_, inodo, err := v.ResolvePath(ruta)
+	if err != nil || inodo.I_type != FS.TipoArchivo {
+		return -1
+	}
+	return inodo.I_size
+}
+
+// verificarTamaño compara el tamaño del archivo reproducido con el contenido
+// del journal: mkfile y edit dejan exactamente ese contenido y append lo
+// agrega al tamaño anterior. Una diferencia se reporta como fallo.
+func verificarTamaño(v *FS.Volumen, op FS.OperacionJournal, antes int64) error {
+	esperado := int64(len(op.Contenido))
+	switch op.Operacion {
+	case "mkfile", "edit":
+	case "append":
+		esperado += antes
+	default:
+		return nil
+	}
+
+	if real := tamañoArchivo(v, op.Ruta); real != esperado {
+		return fmt.Errorf("%s quedó con %d bytes, el journal registra %d", op.Ruta, real, esperado)
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosREMOVE valida los parámetros del comando REMOVE
+func ValidarDatosREMOVE(tokens []string) string {
+	path := ""
+	for _, token := range tokens {
+		tk := strings.SplitN(token, "=", 2)
+		if len(tk) != 2 {
+			continue
+		}
+		switch strings.ToLower(tk[0]) {
+		case "path":
+			path = strings.ReplaceAll(tk[1], "\"", "")
+		default:
+			return Utils.Error("REMOVE", "Parámetro no reconocido: "+tk[0])
+		}
+	}
+
+	if path == "" {
+		return Utils.Error("REMOVE", "El parámetro -path es obligatorio")
+	}
+	if !EstaLogueado() {
+		return Utils.Error("REMOVE", "Debe iniciar sesión para ejecutar este comando")
+	}
+
+	return remove(path)
+}
+
+// remove elimina el archivo o la carpeta path con todo su contenido. Primero
+// recorre el subárbol verificando permisos de escritura; si falta alguno no
+// se elimina nada.
+func remove(path string) string {
+	fmt.Printf("🔧 DEBUG: REMOVE path='%s'\n", path)
+
+	sesion := ObtenerSesionActiva()
+	v, err := abrirVolumen("REMOVE", sesion.Id)
+	if err != nil {
+		return Utils.Error("REMOVE", err.Error())
+	}
+	defer v.Cerrar()
+
+	libresAntes := v.Super.S_free_blocks_count
+	inodos, err := contextoSesion(v).eliminarRuta(path)
+	if err != nil {
+		return Utils.Error("REMOVE", err.Error())
+	}
+
+	return Utils.Mensaje("REMOVE", fmt.Sprintf("Se eliminó '%s' (%d inodos y %d bloques liberados)",
+		path, inodos, v.Super.S_free_blocks_count-libresAntes))
+}
+
+// eliminarRuta verifica y registra la eliminación de path y luego libera sus
+// inodos y bloques. Retorna la cantidad de inodos liberados.
+func (c *contextoFS) eliminarRuta(path string) (int, error) {
+	componentes := FS.SepararRuta(path)
+	if len(componentes) == 0 {
+		return 0, fmt.Errorf("no se puede eliminar la carpeta raíz")
+	}
+	nombre := componentes[len(componentes)-1]
+	rutaPadre := "/" + strings.Join(componentes[:len(componentes)-1], "/")
+
+	nPadre, _, err := c.v.ResolvePath(rutaPadre)
+	if err != nil {
+		return 0, err
+	}
+	nInodo, _, err := c.v.ResolvePath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// La raíz y users.txt son necesarios para LOGIN y la administración de
+	// usuarios y grupos
+	if nombre == "." || nombre == ".." || nInodo == 0 {
+		return 0, fmt.Errorf("no se puede eliminar la carpeta raíz ni las entradas '.' y '..'")
+	}
+	if nUsers, _, err := c.v.ResolvePath("/users.txt"); err == nil && nInodo == nUsers {
+		return 0, fmt.Errorf("no se puede eliminar /users.txt")
+	}
+
+	// 1. Recolectar el subárbol y verificar permisos sin modificar nada
+	var inodos []int64
+	vistos := map[int64]bool{}
+	if err := c.recolectarEliminables(nInodo, "/"+strings.Join(componentes, "/"), vistos, &inodos); err != nil {
+		return 0, err
+	}
+
+	// Registrar la operación en el journal antes de modificar el disco
+	if err := c.registrar("remove", path, ""); err != nil {
+		return 0, err
+	}
+
+	// 2. Quitar la entrada del padre y liberar inodos y bloques
+	if err := c.v.QuitarEntrada(nPadre, nombre); err != nil {
+		return 0, err
+	}
+	for _, n := range inodos {
+		if err := c.v.EliminarInodo(n); err != nil {
+			return 0, fmt.Errorf("error al liberar el inodo %d: %v", n, err)
+		}
+	}
+	return len(inodos), nil
+}
+
+// recolectarEliminables agrega n y sus descendientes a inodos, hijos antes que
+// padres. Falla en el primer elemento sin permiso de escritura.
+func (c *contextoFS) recolectarEliminables(n int64, ruta string, vistos map[int64]bool, inodos *[]int64) error {
+	if vistos[n] {
+		return nil
+	}
+	vistos[n] = true
+
+	inodo, err := c.v.ReadInode(n)
+	if err != nil {
+		return err
+	}
+	if !c.puede(inodo, permisoEscritura) {
+		return fmt.Errorf("no tiene permiso de escritura sobre %s; no se eliminó nada", ruta)
+	}
+
+	if inodo.I_type == FS.TipoCarpeta {
+		entradas, err := c.v.Entradas(inodo)
+		if err != nil {
+			return err
+		}
+		for _, e := range entradas {
+			if e.Nombre == "." || e.Nombre == ".." {
+				continue
+			}
+			if err := c.recolectarEliminables(e.Inodo, strings.TrimSuffix(ruta, "/")+"/"+e.Nombre, vistos, inodos); err != nil {
+				return err
+			}
+		}
+	}
+
+	*inodos = append(*inodos, n)
+	return nil
+}
@@ -0,0 +1,96 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Utils"
+)
+
+// ValidarDatosRENAME valida los parámetros del comando RENAME
+func ValidarDatosRENAME(tokens []string) string {
+	path := ""
+	nombre := ""
+	for _, token := range tokens {
+		tk := strings.SplitN(token, "=", 2)
+		if len(tk) != 2 {
+			continue
+		}
+		switch strings.ToLower(tk[0]) {
+		case "path":
+			path = strings.ReplaceAll(tk[1], "\"", "")
+		case "name":
+			nombre = strings.ReplaceAll(tk[1], "\"", "")
+		default:
+			return Utils.Error("RENAME", "Parámetro no reconocido: "+tk[0])
+		}
+	}
+
+	if path == "" || nombre == "" {
+		return Utils.Error("RENAME", "Los parámetros -path y -name son obligatorios")
+	}
+	if !EstaLogueado() {
+		return Utils.Error("RENAME", "Debe iniciar sesión para ejecutar este comando")
+	}
+
+	return rename(path, nombre)
+}
+
+// rename cambia el nombre del archivo o carpeta path por nombre, dentro de la
+// misma carpeta padre
+func rename(path, nombre string) string {
+	fmt.Printf("🔧 DEBUG: RENAME path='%s' name='%s'\n", path, nombre)
+
+	sesion := ObtenerSesionActiva()
+	v, err := abrirVolumen("RENAME", sesion.Id)
+	if err != nil {
+		return Utils.Error("RENAME", err.Error())
+	}
+	defer v.Cerrar()
+
+	if err := contextoSesion(v).renombrarRuta(path, nombre); err != nil {
+		return Utils.Error("RENAME", err.Error())
+	}
+	return Utils.Mensaje("RENAME", fmt.Sprintf("'%s' renombrado a '%s'", path, nombre))
+}
+
+// renombrarRuta verifica y registra el cambio de nombre y luego modifica la
+// entrada en la carpeta padre
+func (c *contextoFS) renombrarRuta(path, nombre string) error {
+	componentes := FS.SepararRuta(path)
+	if len(componentes) == 0 {
+		return fmt.Errorf("no se puede renombrar la carpeta raíz")
+	}
+	anterior := componentes[len(componentes)-1]
+	if anterior == "." || anterior == ".." {
+		return fmt.Errorf("no se pueden renombrar las entradas '.' y '..'")
+	}
+	rutaPadre := "/" + strings.Join(componentes[:len(componentes)-1], "/")
+
+	nPadre, padre, err := c.v.ResolvePath(rutaPadre)
+	if err != nil {
+		return err
+	}
+	_, inodo, err := c.v.ResolvePath(path)
+	if err != nil {
+		return err
+	}
+	if !c.puede(inodo, permisoEscritura) {
+		return fmt.Errorf("no tiene permiso de escritura sobre %s", path)
+	}
+	if err := FS.ValidarNombre(nombre); err != nil {
+		return err
+	}
+	if existente, err := c.v.BuscarEnDirectorio(padre, nombre); err != nil {
+		return err
+	} else if existente != -1 {
+		return fmt.Errorf("ya existe '%s' en %s", nombre, rutaPadre)
+	}
+
+	// Registrar la operación en el journal antes de modificar el disco
+	if err := c.registrar("rename", path, nombre); err != nil {
+		return err
+	}
+	return c.v.RenombrarEntrada(nPadre, anterior, nombre)
+}
@@ -2,8 +2,12 @@ package Comandos
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
+	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
 
@@ -17,10 +21,16 @@ func ValidarDatosREP(tokens []string) string {
 	path := ""
 	id := ""
 	pathFile := ""
+	resumen := false
 
 	// Parsear tokens
 	for i := 0; i < len(tokens); i++ {
 		token := tokens[i]
+		if lower := strings.ToLower(strings.TrimSpace(token)); lower == "-resumen" || lower == "resumen" {
+			resumen = true
+			continue
+		}
+
 		tk := strings.Split(token, "=")
 		if len(tk) != 2 {
 			continue
@@ -64,120 +74,106 @@ func ValidarDatosREP(tokens []string) string {
 		return Utils.Error("REP", "Los parámetros name, path e id son obligatorios")
 	}
 
-	// Validar tipos de reporte
-	validReports := []string{"MBR", "DISK", "INODE", "JOURNALING", "BLOCK", "BM_INODE", "BM_BLOCK", "TREE", "SB", "FILE", "LS"}
-	if !Utils.ValidarParametro(name, validReports) {
-		return Utils.Error("REP", "Tipos de reporte válidos: MBR, DISK, INODE, JOURNALING, BLOCK, BM_INODE, BM_BLOCK, TREE, SB, FILE, LS")
+	// Validar tipo de reporte y formato contra el registro
+	reporte, ok := registroReportes[name]
+	if !ok {
+		return Utils.Error("REP", "Tipos de reporte válidos: "+strings.Join(nombresReportes(), ", "))
+	}
+	if formatos := reporte.Formatos(); formatos != nil {
+		ext := strings.ToLower(filepath.Ext(path))
+		if !Utils.ValidarParametro(ext, formatos) {
+			return Utils.Error("REP", fmt.Sprintf("El reporte %s no se genera en '%s'. Formatos válidos: %s", name, ext, strings.Join(formatos, ", ")))
+		}
 	}
 
 	// Ejecutar reporte
-	return generarReporte(name, path, id, pathFile)
+	return generarReporte(name, reporte, &contextoReporte{Id: id, Salida: path, PathFileLs: pathFile, Resumen: resumen})
 }
 
-// generarReporte genera el reporte solicitado
-func generarReporte(name, path, id, pathFile string) string {
-	fmt.Printf("🔧 DEBUG: Generando reporte - Type: %s, Path: %s, ID: %s\n", name, path, id)
-
-	// Verificar que la partición esté montada
-	diskPath := ""
-	particion := GetMount("REP", id, &diskPath)
-	if particion == nil {
-		return Utils.Error("REP", "La partición no está montada o el ID es inválido")
-	}
-
-	// Crear directorio de destino si no existe
-	if err := Utils.CrearDirectorio(path); err != nil {
-		return Utils.Error("REP", "Error al crear directorio: "+err.Error())
-	}
-
-	// Generar reporte según el tipo
-	switch name {
-	case "MBR":
-		return generarReporteMBR(path, diskPath, id)
-	case "DISK":
-		return generarReporteDisk(path, diskPath, id)
-	case "INODE":
-		return generarReporteInode(path, diskPath, id)
-	case "JOURNALING":
-		return generarReporteJournaling(path, diskPath, id)
-	case "BLOCK":
-		return generarReporteBlock(path, diskPath, id)
-	case "BM_INODE":
-		return generarReporteBMInode(path, diskPath, id)
-	case "BM_BLOCK":
-		return generarReporteBMBlock(path, diskPath, id)
-	case "TREE":
-		return generarReporteTree(path, diskPath, id)
-	case "SB":
-		return generarReporteSB(path, diskPath, id)
-	case "FILE":
-		if pathFile == "" {
-			return Utils.Error("REP", "El reporte FILE requiere el parámetro path_file_ls")
-		}
-		return generarReporteFile(path, diskPath, id, pathFile)
-	case "LS":
-		if pathFile == "" {
-			return Utils.Error("REP", "El reporte LS requiere el parámetro path_file_ls")
-		}
-		return generarReporteLS(path, diskPath, id, pathFile)
-	default:
-		return Utils.Error("REP", "Tipo de reporte no implementado: "+name)
-	}
+// contextoReporte son los datos que recibe cada reporte. El reporte puede
+// cambiar Salida (FILE usa el nombre del archivo virtual) y dejar en Detalle
+// un texto que se agrega al mensaje final.
+type contextoReporte struct {
+	Id         string
+	DiskPath   string
+	Particion  Structs.Particion
+	Salida     string
+	PathFileLs string
+	Resumen    bool
+	Detalle    string
 }
 
-// Funciones de generación de reportes (implementaciones básicas)
-
-func generarReporteMBR(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte MBR
-	return Utils.Mensaje("REP", "Reporte MBR generado correctamente (pendiente de implementar)")
+// Reporter es un tipo de reporte registrado para REP. Formatos retorna las
+// extensiones de -path que admite; nil indica que el contenido se escribe tal
+// cual, sin importar la extensión.
+type Reporter interface {
+	Formatos() []string
+	Generar(ctx *contextoReporte) (modeloReporte, error)
 }
 
-func generarReporteDisk(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte DISK
-	return Utils.Mensaje("REP", "Reporte DISK generado correctamente (pendiente de implementar)")
+// funcionReporte adapta una función generadora a Reporter
+type funcionReporte struct {
+	formatos []string
+	generar  func(ctx *contextoReporte) (modeloReporte, error)
 }
 
-func generarReporteInode(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte INODE
-	return Utils.Mensaje("REP", "Reporte INODE generado correctamente (pendiente de implementar)")
-}
+func (f funcionReporte) Formatos() []string { return f.formatos }
 
-func generarReporteJournaling(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte JOURNALING
-	return Utils.Mensaje("REP", "Reporte JOURNALING generado correctamente (pendiente de implementar)")
+func (f funcionReporte) Generar(ctx *contextoReporte) (modeloReporte, error) {
+	return f.generar(ctx)
 }
 
-func generarReporteBlock(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte BLOCK
-	return Utils.Mensaje("REP", "Reporte BLOCK generado correctamente (pendiente de implementar)")
-}
+// registroReportes asocia cada nombre de -name con su reporte
+var registroReportes = map[string]Reporter{}
 
-func generarReporteBMInode(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte BM_INODE
-	return Utils.Mensaje("REP", "Reporte BM_INODE generado correctamente (pendiente de implementar)")
+// registrarReporte agrega un tipo de reporte al registro de REP
+func registrarReporte(nombre string, reporte Reporter) {
+	if _, existe := registroReportes[nombre]; existe {
+		panic("reporte registrado dos veces: " + nombre)
+	}
+	registroReportes[nombre] = reporte
 }
 
-func generarReporteBMBlock(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte BM_BLOCK
-	return Utils.Mensaje("REP", "Reporte BM_BLOCK generado correctamente (pendiente de implementar)")
+// nombresReportes retorna los nombres registrados en orden alfabético
+func nombresReportes() []string {
+	nombres := make([]string, 0, len(registroReportes))
+	for nombre := range registroReportes {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	return nombres
 }
 
-func generarReporteTree(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte TREE
-	return Utils.Mensaje("REP", "Reporte TREE generado correctamente (pendiente de implementar)")
-}
+// generarReporte genera el reporte solicitado y lo escribe en el formato de
+// la extensión de -path
+func generarReporte(name string, reporte Reporter, ctx *contextoReporte) string {
+	fmt.Printf("🔧 DEBUG: Generando reporte - Type: %s, Path: %s, ID: %s\n", name, ctx.Salida, ctx.Id)
 
-func generarReporteSB(outputPath, diskPath, id string) string {
-	// TODO: Implementar reporte SB
-	return Utils.Mensaje("REP", "Reporte SB generado correctamente (pendiente de implementar)")
-}
+	// Verificar que la partición esté montada
+	particion := GetMount("REP", ctx.Id, &ctx.DiskPath)
+	if particion == nil {
+		return Utils.Error("REP", "La partición no está montada o el ID es inválido")
+	}
+	ctx.Particion = *particion
 
-func generarReporteFile(outputPath, diskPath, id, pathFile string) string {
-	// TODO: Implementar reporte FILE
-	return Utils.Mensaje("REP", "Reporte FILE generado correctamente (pendiente de implementar)")
-}
+	// Crear directorio de destino si no existe
+	if err := Utils.CrearDirectorio(ctx.Salida); err != nil {
+		return Utils.Error("REP", "Error al crear directorio: "+err.Error())
+	}
 
-func generarReporteLS(outputPath, diskPath, id, pathFile string) string {
-	// TODO: Implementar reporte LS
-	return Utils.Mensaje("REP", "Reporte LS generado correctamente (pendiente de implementar)")
+	modelo, err := reporte.Generar(ctx)
+	if err != nil {
+		return Utils.Error("REP", err.Error())
+	}
+
+	var datos []byte
+	if reporte.Formatos() == nil {
+		datos = []byte(modelo.aTexto())
+	} else if datos, err = renderizarReporte(modelo, filepath.Ext(ctx.Salida)); err != nil {
+		return Utils.Error("REP", err.Error())
+	}
+	if err := os.WriteFile(ctx.Salida, datos, 0644); err != nil {
+		return Utils.Error("REP", "Error al escribir el reporte: "+err.Error())
+	}
+	return Utils.Mensaje("REP", fmt.Sprintf("Reporte %s generado en %s%s", name, ctx.Salida, ctx.Detalle))
 }
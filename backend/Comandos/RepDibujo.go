@@ -0,0 +1,113 @@
+package Comandos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dibujo es la representación vectorial de un reporte: un lienzo de Ancho x
+// Alto unidades con sus figuras en orden de pintado. La misma distribución se
+// exporta a SVG o se rasteriza para PNG, JPG y PDF.
+type dibujo struct {
+	Ancho, Alto int
+	figuras     []figura
+}
+
+// figura es un elemento del dibujo que sabe escribirse en SVG y pintarse en
+// un lienzo de píxeles
+type figura interface {
+	aSVG(sb *strings.Builder)
+	rasterizar(l *lienzo)
+}
+
+// figRect es un rectángulo relleno; sin borde si borde está vacío
+type figRect struct {
+	x, y, ancho, alto int
+	relleno, borde    string
+}
+
+// figLinea es una línea recta, opcionalmente con punta de flecha al final
+type figLinea struct {
+	x1, y1, x2, y2 int
+	color          string
+	flecha         bool
+}
+
+// figTexto es una línea de texto; y es la línea base
+type figTexto struct {
+	x, y                    int
+	contenido, color        string
+	tamaño                  int
+	negrita, centrado, mono bool
+}
+
+// rectangulo agrega un rectángulo al dibujo
+func (d *dibujo) rectangulo(x, y, ancho, alto int, relleno, borde string) {
+	d.figuras = append(d.figuras, figRect{x, y, ancho, alto, relleno, borde})
+}
+
+// linea agrega una línea al dibujo
+func (d *dibujo) linea(x1, y1, x2, y2 int, color string, flecha bool) {
+	d.figuras = append(d.figuras, figLinea{x1, y1, x2, y2, color, flecha})
+}
+
+// texto agrega una línea de texto al dibujo
+func (d *dibujo) texto(t figTexto) {
+	d.figuras = append(d.figuras, t)
+}
+
+// aSVG escribe el dibujo como documento SVG
+func (d dibujo) aSVG() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" font-family=\"Helvetica\" font-size=\"12\">\n",
+		d.Ancho, d.Alto))
+	for _, f := range d.figuras {
+		if l, ok := f.(figLinea); ok && l.flecha {
+			sb.WriteString("<defs><marker id=\"flecha\" markerWidth=\"10\" markerHeight=\"7\" refX=\"10\" refY=\"3.5\" orient=\"auto\">" +
+				"<polygon points=\"0 0, 10 3.5, 0 7\" fill=\"#333\"/></marker></defs>\n")
+			break
+		}
+	}
+	sb.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+	for _, f := range d.figuras {
+		f.aSVG(&sb)
+	}
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+func (r figRect) aSVG(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" fill=\"%s\"", r.x, r.y, r.ancho, r.alto, r.relleno))
+	if r.borde != "" {
+		sb.WriteString(fmt.Sprintf(" stroke=\"%s\"", r.borde))
+	}
+	sb.WriteString("/>\n")
+}
+
+func (l figLinea) aSVG(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"%s\"", l.x1, l.y1, l.x2, l.y2, l.color))
+	if l.flecha {
+		sb.WriteString(" marker-end=\"url(#flecha)\"")
+	}
+	sb.WriteString("/>\n")
+}
+
+func (t figTexto) aSVG(sb *strings.Builder) {
+	sb.WriteString(fmt.Sprintf("<text x=\"%d\" y=\"%d\"", t.x, t.y))
+	if t.tamaño != 0 {
+		sb.WriteString(fmt.Sprintf(" font-size=\"%d\"", t.tamaño))
+	}
+	if t.mono {
+		sb.WriteString(" font-family=\"monospace\"")
+	}
+	if t.color != "" {
+		sb.WriteString(fmt.Sprintf(" fill=\"%s\"", t.color))
+	}
+	if t.negrita {
+		sb.WriteString(" font-weight=\"bold\"")
+	}
+	if t.centrado {
+		sb.WriteString(" text-anchor=\"middle\"")
+	}
+	sb.WriteString(" xml:space=\"preserve\">" + escaparMarcado(t.contenido) + "</text>\n")
+}
@@ -0,0 +1,164 @@
+package Comandos
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"godisk-backend/Structs"
+	"godisk-backend/Utils"
+)
+
+func init() {
+	registrarReporte("MBR", funcionReporte{formatosTodos, reporteMBR})
+	registrarReporte("DISK", funcionReporte{formatosTodos, reporteDisk})
+}
+
+// reporteMBR reporta el MBR del disco, sus cuatro particiones y los EBR de la
+// cadena de la extendida
+func reporteMBR(ctx *contextoReporte) (modeloReporte, error) {
+	mbr := leerDisco(ctx.DiskPath)
+	if mbr == nil {
+		return nil, fmt.Errorf("no se pudo leer el MBR del disco %s", ctx.DiskPath)
+	}
+
+	tabla := tablaReporte{Titulo: "REPORTE MBR - " + ctx.Id}
+
+	seccion := seccionReporte{Titulo: "MBR", Color: "#4a235a"}
+	seccion.agregarFila("mbr_tamano", mbr.Mbr_tamano)
+	seccion.agregarFila("mbr_fecha_creacion", convertirFechaAString(mbr.Mbr_fecha_creacion))
+	seccion.agregarFila("mbr_disk_signature", mbr.Mbr_dsk_signature)
+	seccion.agregarFila("dsk_fit", convertirFitAString(mbr.Dsk_fit))
+	seccion.agregarFila("mbr_version", mbr.Mbr_version)
+	tabla.Secciones = append(tabla.Secciones, seccion)
+
+	for i, particion := range getParticiones(*mbr) {
+		seccion := seccionReporte{Titulo: fmt.Sprintf("Partición %d", i+1), Color: "#1f618d"}
+		seccion.agregarFila("part_status", caracterReporte(particion.Part_status))
+		seccion.agregarFila("part_type", caracterReporte(particion.Part_type))
+		seccion.agregarFila("part_fit", caracterReporte(particion.Part_fit))
+		seccion.agregarFila("part_start", particion.Part_start)
+		seccion.agregarFila("part_size", particion.Part_size)
+		seccion.agregarFila("part_name", Utils.ConvertirAString(particion.Part_name))
+		tabla.Secciones = append(tabla.Secciones, seccion)
+
+		if particion.Part_status != '1' || particion.Part_type != 'E' {
+			continue
+		}
+
+		cadena, err := leerCadenaEBR(ctx.DiskPath, particion)
+		if err != nil {
+			return nil, fmt.Errorf("error al leer la cadena de EBR: %v", err)
+		}
+		for _, actual := range cadena {
+			ebr := actual.ebr
+			seccion := seccionReporte{Titulo: fmt.Sprintf("EBR en %d", actual.posicion), Color: "#922b21"}
+			seccion.agregarFila("part_status", caracterReporte(ebr.Part_status))
+			seccion.agregarFila("part_fit", caracterReporte(ebr.Part_fit))
+			seccion.agregarFila("part_start", ebr.Part_start)
+			seccion.agregarFila("part_size", ebr.Part_size)
+			seccion.agregarFila("part_next", ebr.Part_next)
+			seccion.agregarFila("part_name", Utils.ConvertirAString(ebr.Part_name))
+			tabla.Secciones = append(tabla.Secciones, seccion)
+		}
+	}
+
+	return tabla, nil
+}
+
+// caracterReporte muestra un campo de un byte; los vacíos se muestran como "-"
+func caracterReporte(b byte) string {
+	if b == 0 {
+		return "-"
+	}
+	return string(b)
+}
+
+// reporteDisk reporta la distribución del disco: MBR, particiones, EBR y
+// lógicas dentro de la extendida y espacios libres, cada uno con su
+// porcentaje del tamaño del disco
+func reporteDisk(ctx *contextoReporte) (modeloReporte, error) {
+	mbr := leerDisco(ctx.DiskPath)
+	if mbr == nil {
+		return nil, fmt.Errorf("no se pudo leer el MBR del disco %s", ctx.DiskPath)
+	}
+
+	barra := barraReporte{
+		Titulo: fmt.Sprintf("REPORTE DISK - %s (%s)", ctx.Id, filepath.Base(ctx.DiskPath)),
+		Total:  mbr.Mbr_tamano,
+	}
+	segmentos := []segmentoReporte{{Nombre: "MBR", Inicio: 0, Tamaño: Structs.TamMBR, Color: "#4a235a"}}
+
+	particiones := getParticiones(*mbr)
+	for _, particion := range particiones {
+		if particion.Part_status != '1' {
+			continue
+		}
+		segmento := segmentoReporte{
+			Nombre:  Utils.ConvertirAString(particion.Part_name),
+			Detalle: "Primaria",
+			Inicio:  particion.Part_start,
+			Tamaño:  particion.Part_size,
+			Color:   "#1f618d",
+		}
+		if particion.Part_type == 'E' {
+			segmento.Detalle = "Extendida"
+			segmento.Color = "#117864"
+			hijos, err := segmentosExtendida(ctx.DiskPath, particion)
+			if err != nil {
+				return nil, fmt.Errorf("error al leer la cadena de EBR: %v", err)
+			}
+			segmento.Hijos = hijos
+		}
+		segmentos = append(segmentos, segmento)
+	}
+
+	// Los espacios libres son los mismos que ve FDISK
+	for _, espacio := range calcularEspaciosLibres(*mbr, particiones) {
+		segmentos = append(segmentos, segmentoLibre(int64(espacio.inicio), int64(espacio.tamaño)))
+	}
+	ordenarSegmentos(segmentos)
+	barra.Segmentos = segmentos
+
+	return barra, nil
+}
+
+// segmentosExtendida arma los segmentos de EBR, lógicas y espacios libres
+// dentro de la extendida
+func segmentosExtendida(diskPath string, extendida Structs.Particion) ([]segmentoReporte, error) {
+	cadena, err := leerCadenaEBR(diskPath, extendida)
+	if err != nil {
+		return nil, err
+	}
+
+	var segmentos []segmentoReporte
+	for _, actual := range cadena {
+		if actual.ebr.Part_status != '1' {
+			continue
+		}
+		segmentos = append(segmentos,
+			segmentoReporte{Nombre: "EBR", Inicio: actual.posicion, Tamaño: Structs.TamEBR, Color: "#922b21"},
+			segmentoReporte{
+				Nombre:  Utils.ConvertirAString(actual.ebr.Part_name),
+				Detalle: "Lógica",
+				Inicio:  actual.ebr.Part_start,
+				Tamaño:  actual.ebr.Part_size,
+				Color:   "#b9770e",
+			})
+	}
+	for _, espacio := range calcularEspaciosLibresExtendida(extendida, cadena) {
+		segmentos = append(segmentos, segmentoLibre(int64(espacio.inicio), int64(espacio.tamaño)))
+	}
+	ordenarSegmentos(segmentos)
+	return segmentos, nil
+}
+
+// segmentoLibre crea el segmento de un espacio libre
+func segmentoLibre(inicio, tamaño int64) segmentoReporte {
+	return segmentoReporte{Nombre: "Libre", Inicio: inicio, Tamaño: tamaño, Color: "#7f8c8d"}
+}
+
+// ordenarSegmentos ordena los segmentos por posición de inicio
+func ordenarSegmentos(segmentos []segmentoReporte) {
+	sort.Slice(segmentos, func(i, j int) bool { return segmentos[i].Inicio < segmentos[j].Inicio })
+}
@@ -0,0 +1,699 @@
+package Comandos
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"godisk-backend/FS"
+	"godisk-backend/Structs"
+	"godisk-backend/Utils"
+)
+
+// Tipos de bloque según la estructura que contienen
+const (
+	bloqueCarpeta     = "carpeta"
+	bloqueArchivo     = "archivo"
+	bloqueApuntadores = "apuntadores"
+)
+
+// formatosBitmap son las extensiones de los reportes de bitmap, que son texto
+var formatosBitmap = []string{".txt", ".md", ".html", ".json"}
+
+func init() {
+	registrarReporte("INODE", funcionReporte{formatosTodos, reporteInode})
+	registrarReporte("BLOCK", funcionReporte{formatosTodos, reporteBlock})
+	registrarReporte("TREE", funcionReporte{formatosTodos, reporteTree})
+	registrarReporte("SB", funcionReporte{formatosTodos, reporteSB})
+	registrarReporte("BM_INODE", funcionReporte{formatosBitmap, reporteBitmap(true)})
+	registrarReporte("BM_BLOCK", funcionReporte{formatosBitmap, reporteBitmap(false)})
+	registrarReporte("FILE", funcionReporte{nil, reporteFile})
+	registrarReporte("LS", funcionReporte{formatosTodos, reporteLS})
+	registrarReporte("JOURNALING", funcionReporte{formatosTodos, reporteJournaling})
+}
+
+// reporteInode reporta todos los inodos marcados como usados en el bitmap,
+// enlazados en orden
+func reporteInode(ctx *contextoReporte) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	bitmap, err := v.LeerBitmapInodos()
+	if err != nil {
+		return nil, err
+	}
+
+	grafo := grafoReporte{Titulo: "REPORTE INODE - " + ctx.Id}
+	anterior := ""
+	for _, n := range usadosEnBitmap(bitmap) {
+		inodo, err := v.ReadInode(n)
+		if err != nil {
+			return nil, err
+		}
+
+		nodo := nodoInodo(n, inodo)
+		grafo.Nodos = append(grafo.Nodos, nodo)
+
+		if anterior != "" {
+			grafo.Aristas = append(grafo.Aristas, aristaReporte{Desde: anterior, Hasta: nodo.ID})
+		}
+		anterior = nodo.ID
+	}
+
+	ctx.Detalle = fmt.Sprintf(" (%d inodos)", len(grafo.Nodos))
+	return grafo, nil
+}
+
+// reporteBlock reporta todos los bloques usados según su tipo real: carpeta,
+// archivo o apuntadores, enlazados en orden
+func reporteBlock(ctx *contextoReporte) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	bitmap, err := v.LeerBitmapBloques()
+	if err != nil {
+		return nil, err
+	}
+	tipos := clasificarBloques(v)
+
+	grafo := grafoReporte{Titulo: "REPORTE BLOCK - " + ctx.Id}
+	anterior := ""
+	for _, n := range usadosEnBitmap(bitmap) {
+		nodo, err := nodoBloque(v, n, tipos[n])
+		if err != nil {
+			return nil, err
+		}
+		grafo.Nodos = append(grafo.Nodos, nodo)
+
+		if anterior != "" {
+			grafo.Aristas = append(grafo.Aristas, aristaReporte{Desde: anterior, Hasta: nodo.ID})
+		}
+		anterior = nodo.ID
+	}
+
+	ctx.Detalle = fmt.Sprintf(" (%d bloques)", len(grafo.Nodos))
+	return grafo, nil
+}
+
+// idInodo e idBloque identifican los nodos de inodos y bloques en los grafos
+func idInodo(n int64) string  { return fmt.Sprintf("inodo%d", n) }
+func idBloque(n int64) string { return fmt.Sprintf("bloque%d", n) }
+
+// nodoInodo arma el nodo de un inodo con todos sus campos
+func nodoInodo(n int64, inodo Structs.Inodos) nodoReporte {
+	nodo := nodoReporte{ID: idInodo(n), Titulo: fmt.Sprintf("Inodo %d", n), Color: "#1f618d"}
+	nodo.agregarFila("i_uid", inodo.I_uid)
+	nodo.agregarFila("i_gid", inodo.I_gid)
+	nodo.agregarFila("i_size", inodo.I_size)
+	nodo.agregarFila("i_atime", Utils.ConvertirAString(inodo.I_atime))
+	nodo.agregarFila("i_ctime", Utils.ConvertirAString(inodo.I_ctime))
+	nodo.agregarFila("i_mtime", Utils.ConvertirAString(inodo.I_mtime))
+	for i, b := range inodo.I_block {
+		nodo.agregarFila(fmt.Sprintf("i_block_%d", i+1), b)
+	}
+	nodo.agregarFila("i_type", inodo.I_type)
+	nodo.agregarFila("i_perm", fmt.Sprintf("%03d", inodo.I_perm))
+	return nodo
+}
+
+// nodoBloque arma el nodo de un bloque según su tipo. Un tipo vacío indica
+// un bloque no alcanzable desde la raíz y se muestra como contenido.
+func nodoBloque(v *FS.Volumen, n int64, tipo string) (nodoReporte, error) {
+	nodo := nodoReporte{ID: idBloque(n)}
+
+	switch tipo {
+	case bloqueCarpeta:
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(n, &carpeta); err != nil {
+			return nodo, err
+		}
+		nodo.Titulo = fmt.Sprintf("Bloque Carpeta %d", n)
+		nodo.Color = "#117864"
+		for _, c := range carpeta.B_content {
+			nombre := FS.NombreEntrada(c)
+			if nombre == "" {
+				nombre = "-"
+			}
+			nodo.agregarFila(nombre, c.B_inodo)
+		}
+	case bloqueApuntadores:
+		var ap Structs.BloquesApuntadores
+		if err := v.ReadBlock(n, &ap); err != nil {
+			return nodo, err
+		}
+		nodo.Titulo = fmt.Sprintf("Bloque Apuntadores %d", n)
+		nodo.Color = "#922b21"
+		punteros := make([]string, len(ap.B_pointers))
+		for i, p := range ap.B_pointers {
+			punteros[i] = fmt.Sprint(p)
+		}
+		nodo.agregarFila("b_pointers", strings.Join(punteros, ", "))
+	default:
+		var archivo Structs.BloquesArchivos
+		if err := v.ReadBlock(n, &archivo); err != nil {
+			return nodo, err
+		}
+		nodo.Titulo = fmt.Sprintf("Bloque Archivo %d", n)
+		if tipo == "" {
+			nodo.Titulo = fmt.Sprintf("Bloque %d (sin referencia)", n)
+		}
+		nodo.Color = "#b9770e"
+		nodo.agregarFila("b_content", string(bytes.TrimRight(archivo.B_content[:], "\x00")))
+	}
+	return nodo, nil
+}
+
+// reporteTree grafica el sistema de archivos desde el inodo raíz: cada
+// inodo con sus bloques y cada entrada de carpeta con el inodo al que apunta.
+// Los inodos y bloques ya visitados no se recorren de nuevo, por lo que los
+// ciclos ("." y "..", o enlaces corruptos) no provocan recorridos infinitos.
+func reporteTree(ctx *contextoReporte) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	grafo := grafoReporte{Titulo: "REPORTE TREE - " + ctx.Id}
+	inodosVistos := map[int64]bool{}
+	bloquesVistos := map[int64]bool{}
+	var errores []string
+
+	var visitarInodo func(n int64)
+	var visitarBloque func(n int64, nivel int, tipo string)
+
+	visitarInodo = func(n int64) {
+		if inodosVistos[n] {
+			return
+		}
+		inodosVistos[n] = true
+
+		inodo, err := v.ReadInode(n)
+		if err != nil {
+			errores = append(errores, err.Error())
+			return
+		}
+		grafo.Nodos = append(grafo.Nodos, nodoInodo(n, inodo))
+
+		tipo := bloqueArchivo
+		if inodo.I_type == FS.TipoCarpeta {
+			tipo = bloqueCarpeta
+		}
+		for i, b := range inodo.I_block {
+			if b == -1 {
+				continue
+			}
+			nivel := 0
+			if i >= FS.BloquesDirectos {
+				nivel = i - FS.BloquesDirectos + 1
+			}
+			grafo.Aristas = append(grafo.Aristas, aristaReporte{Desde: idInodo(n), Hasta: idBloque(b), Etiqueta: fmt.Sprintf("i_block_%d", i+1)})
+			visitarBloque(b, nivel, tipo)
+		}
+	}
+
+	visitarBloque = func(n int64, nivel int, tipo string) {
+		if bloquesVistos[n] {
+			return
+		}
+		bloquesVistos[n] = true
+		if n < 0 || n >= v.Super.S_blocks_count {
+			errores = append(errores, fmt.Sprintf("bloque fuera de rango: %d", n))
+			return
+		}
+
+		if nivel > 0 {
+			nodo, err := nodoBloque(v, n, bloqueApuntadores)
+			if err != nil {
+				errores = append(errores, err.Error())
+				return
+			}
+			grafo.Nodos = append(grafo.Nodos, nodo)
+
+			var ap Structs.BloquesApuntadores
+			if err := v.ReadBlock(n, &ap); err != nil {
+				errores = append(errores, err.Error())
+				return
+			}
+			for _, p := range ap.B_pointers {
+				if p == -1 {
+					continue
+				}
+				grafo.Aristas = append(grafo.Aristas, aristaReporte{Desde: idBloque(n), Hasta: idBloque(int64(p))})
+				visitarBloque(int64(p), nivel-1, tipo)
+			}
+			return
+		}
+
+		nodo, err := nodoBloque(v, n, tipo)
+		if err != nil {
+			errores = append(errores, err.Error())
+			return
+		}
+		grafo.Nodos = append(grafo.Nodos, nodo)
+		if tipo != bloqueCarpeta {
+			return
+		}
+
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(n, &carpeta); err != nil {
+			errores = append(errores, err.Error())
+			return
+		}
+		for _, c := range carpeta.B_content {
+			nombre := FS.NombreEntrada(c)
+			if c.B_inodo == -1 || nombre == "." || nombre == ".." {
+				continue
+			}
+			if c.B_inodo < 0 || c.B_inodo >= v.Super.S_inodes_count {
+				errores = append(errores, fmt.Sprintf("la entrada '%s' apunta al inodo fuera de rango %d", nombre, c.B_inodo))
+				continue
+			}
+			grafo.Aristas = append(grafo.Aristas, aristaReporte{Desde: idBloque(n), Hasta: idInodo(c.B_inodo), Etiqueta: nombre})
+			visitarInodo(c.B_inodo)
+		}
+	}
+
+	visitarInodo(0)
+
+	// Los enlaces a estructuras que no se pudieron leer se descartan
+	presentes := titulosNodos(grafo)
+	aristas := grafo.Aristas[:0]
+	for _, a := range grafo.Aristas {
+		if _, ok := presentes[a.Hasta]; ok {
+			aristas = append(aristas, a)
+		}
+	}
+	grafo.Aristas = aristas
+
+	ctx.Detalle = fmt.Sprintf(" (%d inodos, %d bloques)", len(inodosVistos), len(bloquesVistos))
+	if len(errores) > 0 {
+		ctx.Detalle += "\n   ⚠️ " + strings.Join(errores, "\n   ⚠️ ")
+	}
+	return grafo, nil
+}
+
+// reporteSB reporta todos los campos del superbloque, el tamaño de
+// cada región y marca las que quedan fuera de la partición
+func reporteSB(ctx *contextoReporte) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+	spr := v.Super
+
+	tabla := tablaReporte{Titulo: "REPORTE SUPERBLOQUE - " + ctx.Id}
+
+	campos := seccionReporte{Titulo: "Superbloque", Color: "#1f618d"}
+	campos.agregarFila("s_filesystem_type", spr.S_filesystem_type)
+	campos.agregarFila("s_inodes_count", spr.S_inodes_count)
+	campos.agregarFila("s_blocks_count", spr.S_blocks_count)
+	campos.agregarFila("s_free_inodes_count", spr.S_free_inodes_count)
+	campos.agregarFila("s_free_blocks_count", spr.S_free_blocks_count)
+	campos.agregarFila("s_mtime", Utils.ConvertirAString(spr.S_mtime))
+	campos.agregarFila("s_umtime", Utils.ConvertirAString(spr.S_umtime))
+	campos.agregarFila("s_mnt_count", spr.S_mnt_count)
+	campos.agregarFila("s_magic", fmt.Sprintf("0x%X", spr.S_magic))
+	campos.agregarFila("s_inode_size", spr.S_inode_size)
+	campos.agregarFila("s_block_size", spr.S_block_size)
+	campos.agregarFila("s_firts_ino", spr.S_firts_ino)
+	campos.agregarFila("s_first_blo", spr.S_first_blo)
+	campos.agregarFila("s_bm_inode_start", spr.S_bm_inode_start)
+	campos.agregarFila("s_bm_block_start", spr.S_bm_block_start)
+	campos.agregarFila("s_inode_start", spr.S_inode_start)
+	campos.agregarFila("s_block_start", spr.S_block_start)
+	campos.agregarFila("s_journal_start", spr.S_journal_start)
+	campos.agregarFila("s_version", spr.S_version)
+	tabla.Secciones = append(tabla.Secciones, campos)
+
+	// Regiones en el orden en que están en la partición
+	inicioParticion := v.Particion.Part_start
+	finParticion := v.Particion.Part_start + v.Particion.Part_size
+	type region struct {
+		nombre      string
+		inicio, tam int64
+	}
+	regiones := []region{{"Superbloque", inicioParticion, Structs.TamSuperBloque}}
+	if v.EsEXT3() {
+		regiones = append(regiones, region{"Journal", spr.S_journal_start, spr.S_bm_inode_start - spr.S_journal_start})
+	}
+	regiones = append(regiones,
+		region{"Bitmap de inodos", spr.S_bm_inode_start, spr.S_inodes_count},
+		region{"Bitmap de bloques", spr.S_bm_block_start, spr.S_blocks_count},
+		region{"Tabla de inodos", spr.S_inode_start, spr.S_inodes_count * spr.S_inode_size},
+		region{"Bloques", spr.S_block_start, spr.S_blocks_count * spr.S_block_size},
+	)
+
+	fuera := 0
+	seccion := seccionReporte{Titulo: "Regiones (valores derivados)", Color: "#117864"}
+	seccion.agregarFila("Partición", fmt.Sprintf("%d - %d (%d bytes)", inicioParticion, finParticion, v.Particion.Part_size))
+	for _, r := range regiones {
+		valor := fmt.Sprintf("%d - %d (%d bytes)", r.inicio, r.inicio+r.tam, r.tam)
+		if r.inicio < inicioParticion || r.inicio+r.tam > finParticion || r.tam < 0 {
+			valor += " ⚠️ FUERA DE LA PARTICIÓN"
+			fuera++
+		}
+		seccion.agregarFila(r.nombre, valor)
+	}
+	seccion.agregarFila("Sin usar al final", finParticion-(spr.S_block_start+spr.S_blocks_count*spr.S_block_size))
+	tabla.Secciones = append(tabla.Secciones, seccion)
+
+	if fuera > 0 {
+		ctx.Detalle = fmt.Sprintf("\n   ⚠️ %d región(es) fuera de la partición", fuera)
+	}
+	return tabla, nil
+}
+
+// reporteFile retorna el contenido completo del archivo virtual, que se
+// escribe en la carpeta de -path con el nombre del archivo virtual
+func reporteFile(ctx *contextoReporte) (modeloReporte, error) {
+	pathFile := ctx.PathFileLs
+	if pathFile == "" {
+		return nil, fmt.Errorf("el reporte FILE requiere el parámetro path_file_ls")
+	}
+
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	_, inodo, err := v.ResolvePath(pathFile)
+	if err != nil {
+		return nil, fmt.Errorf("no se encontró el archivo %s: %v", pathFile, err)
+	}
+	if inodo.I_type != FS.TipoArchivo {
+		return nil, fmt.Errorf("%s no es un archivo", pathFile)
+	}
+	contenido, err := v.LeerArchivo(inodo)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer %s: %v", pathFile, err)
+	}
+
+	carpeta := filepath.Dir(ctx.Salida)
+	if strings.HasSuffix(ctx.Salida, "/") || Utils.EsDirectorio(ctx.Salida) {
+		carpeta = ctx.Salida
+	}
+	ctx.Salida = filepath.Join(carpeta, path.Base(pathFile))
+	ctx.Detalle = fmt.Sprintf(" (%d bytes)", len(contenido))
+	return textoReporte{Contenido: contenido}, nil
+}
+
+// reporteLS lista el contenido de la carpeta ctx.PathFileLs al estilo ls -l.
+// Si la ruta es un archivo, lista solo ese archivo.
+func reporteLS(ctx *contextoReporte) (modeloReporte, error) {
+	pathFile := ctx.PathFileLs
+	if pathFile == "" {
+		return nil, fmt.Errorf("el reporte LS requiere el parámetro path_file_ls")
+	}
+
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	nInodo, inodo, err := v.ResolvePath(pathFile)
+	if err != nil {
+		return nil, fmt.Errorf("no se encontró %s: %v", pathFile, err)
+	}
+
+	_, _, contenidoUsers, err := leerUsuarios(v)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo leer el archivo users.txt")
+	}
+	usuarios, grupos := nombresUsuarios(contenidoUsers)
+	nombre := func(nombres map[int64]string, id int64) string {
+		if n, ok := nombres[id]; ok {
+			return n
+		}
+		return fmt.Sprint(id)
+	}
+
+	entradas := []FS.Entrada{{Nombre: path.Base(pathFile), Inodo: nInodo}}
+	if inodo.I_type == FS.TipoCarpeta {
+		if entradas, err = v.Entradas(inodo); err != nil {
+			return nil, fmt.Errorf("error al leer la carpeta %s: %v", pathFile, err)
+		}
+	}
+
+	seccion := seccionReporte{
+		Titulo:   pathFile,
+		Color:    "#1f618d",
+		Columnas: []string{"Permisos", "Propietario", "Grupo", "Tamaño", "Creación", "Modificación", "Tipo", "Nombre"},
+	}
+	for _, e := range entradas {
+		if e.Nombre == "." || e.Nombre == ".." {
+			continue
+		}
+		hijo, err := v.ReadInode(e.Inodo)
+		if err != nil {
+			return nil, err
+		}
+		tipo := "Archivo"
+		if hijo.I_type == FS.TipoCarpeta {
+			tipo = "Carpeta"
+		}
+		seccion.agregarRegistro(
+			cadenaPermisos(hijo),
+			nombre(usuarios, hijo.I_uid),
+			nombre(grupos, hijo.I_gid),
+			hijo.I_size,
+			Utils.ConvertirAString(hijo.I_ctime),
+			Utils.ConvertirAString(hijo.I_mtime),
+			tipo,
+			e.Nombre,
+		)
+	}
+
+	ctx.Detalle = fmt.Sprintf(" (%d entradas)", len(seccion.Filas))
+	return tablaReporte{Titulo: "REPORTE LS - " + ctx.Id, Secciones: []seccionReporte{seccion}}, nil
+}
+
+// cadenaPermisos convierte I_perm (por ejemplo 664) al formato de ls -l
+// (por ejemplo -rw-rw-r--), con "d" al inicio para las carpetas
+func cadenaPermisos(inodo Structs.Inodos) string {
+	var sb strings.Builder
+	if inodo.I_type == FS.TipoCarpeta {
+		sb.WriteByte('d')
+	} else {
+		sb.WriteByte('-')
+	}
+	digitos := fmt.Sprintf("%03d", inodo.I_perm)
+	if len(digitos) != 3 || strings.Trim(digitos, "01234567") != "" {
+		return sb.String() + "?????????"
+	}
+	for _, d := range digitos {
+		valor := d - '0'
+		for i, letra := range "rwx" {
+			if valor&(4>>i) != 0 {
+				sb.WriteRune(letra)
+			} else {
+				sb.WriteByte('-')
+			}
+		}
+	}
+	return sb.String()
+}
+
+// reporteJournaling reporta las entradas del journal de una partición
+// EXT3. Las particiones EXT2 no tienen journal y se rechazan.
+func reporteJournaling(ctx *contextoReporte) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	if !v.EsEXT3() {
+		return nil, fmt.Errorf("la partición %s es EXT2 y no tiene journaling", ctx.Id)
+	}
+	operaciones, err := v.LeerOperaciones()
+	if err != nil {
+		return nil, err
+	}
+	usadas := int64(0)
+	for _, op := range operaciones {
+		usadas += op.Entradas
+	}
+
+	seccion := seccionReporte{
+		Titulo:   fmt.Sprintf("Journal (%d operaciones, %d de %d entradas)", len(operaciones), usadas, v.CapacidadJournal()),
+		Color:    "#1f618d",
+		Columnas: []string{"#", "Operación", "Ruta", "Contenido", "Fecha"},
+	}
+	for _, op := range operaciones {
+		seccion.agregarRegistro(op.Numero, op.Operacion, op.Ruta, contenidoJournal(op), op.Fecha)
+	}
+
+	ctx.Detalle = fmt.Sprintf(" (%d operaciones)", len(operaciones))
+	return tablaReporte{Titulo: "REPORTE JOURNALING - " + ctx.Id, Secciones: []seccionReporte{seccion}}, nil
+}
+
+// maxContenidoJournal es la cantidad de caracteres del contenido que se
+// muestran por operación en el reporte de journaling
+const maxContenidoJournal = 64
+
+// contenidoJournal prepara el contenido de una operación para el reporte:
+// oculta la contraseña de MKUSR y abrevia los contenidos largos indicando
+// su tamaño real
+func contenidoJournal(op FS.OperacionJournal) string {
+	contenido := op.Contenido
+	if op.Operacion == "mkusr" {
+		if campos := strings.Split(contenido, ","); len(campos) == 3 {
+			campos[1] = "********"
+			contenido = strings.Join(campos, ",")
+		}
+	}
+	if runas := []rune(contenido); len(runas) > maxContenidoJournal {
+		contenido = fmt.Sprintf("%s... (%d bytes)", string(runas[:maxContenidoJournal]), len(op.Contenido))
+	}
+	return contenido
+}
+
+// entradasPorLinea es la cantidad de entradas por línea en los reportes de bitmap
+const entradasPorLinea = 20
+
+// reporteBitmap retorna el generador del bitmap de inodos o de bloques
+func reporteBitmap(inodos bool) func(ctx *contextoReporte) (modeloReporte, error) {
+	return func(ctx *contextoReporte) (modeloReporte, error) {
+		return generarBitmap(ctx, inodos)
+	}
+}
+
+// generarBitmap arma el bitmap indicado con 20 entradas por línea y, si se
+// pide, un resumen de usados y libres comparado contra el superbloque
+func generarBitmap(ctx *contextoReporte, inodos bool) (modeloReporte, error) {
+	v, err := abrirVolumen("REP", ctx.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Cerrar()
+
+	nombre := "BM_BLOCK"
+	var bitmap []byte
+	var inicio, libresSuper int64
+	var campo string
+	if inodos {
+		nombre = "BM_INODE"
+		bitmap, err = v.LeerBitmapInodos()
+		inicio, libresSuper, campo = v.Super.S_bm_inode_start, v.Super.S_free_inodes_count, "S_free_inodes_count"
+	} else {
+		bitmap, err = v.LeerBitmapBloques()
+		inicio, libresSuper, campo = v.Super.S_bm_block_start, v.Super.S_free_blocks_count, "S_free_blocks_count"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sb strings.Builder
+
+	usados, invalidos := int64(0), int64(0)
+	for i, b := range bitmap {
+		switch b {
+		case FS.BitmapOcupado:
+			usados++
+			sb.WriteByte('1')
+		case FS.BitmapLibre:
+			sb.WriteByte('0')
+		default:
+			// Valores corruptos (por ejemplo, tras LOSS) se marcan para verlos
+			invalidos++
+			sb.WriteByte('?')
+		}
+		if (i+1)%entradasPorLinea == 0 || i == len(bitmap)-1 {
+			sb.WriteByte('\n')
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+
+	if ctx.Resumen {
+		libres := int64(len(bitmap)) - usados
+		sb.WriteString("\nResumen\n-------\n")
+		sb.WriteString(fmt.Sprintf("Total:   %d\n", len(bitmap)))
+		sb.WriteString(fmt.Sprintf("Usados:  %d\n", usados))
+		sb.WriteString(fmt.Sprintf("Libres:  %d\n", libres))
+		if invalidos > 0 {
+			sb.WriteString(fmt.Sprintf("Entradas inválidas ('?'): %d\n", invalidos))
+		}
+		if libres == libresSuper {
+			sb.WriteString(fmt.Sprintf("%s: %d (coincide)\n", campo, libresSuper))
+		} else {
+			sb.WriteString(fmt.Sprintf("%s: %d ⚠️ NO COINCIDE con el bitmap (%d libres)\n", campo, libresSuper, libres))
+		}
+	}
+
+	titulo := fmt.Sprintf("REPORTE %s - %s (byte %d, %d entradas)", nombre, ctx.Id, inicio, len(bitmap))
+	return textoReporte{Titulo: titulo, Contenido: sb.String()}, nil
+}
+
+// usadosEnBitmap retorna los números marcados como ocupados en un bitmap
+func usadosEnBitmap(bitmap []byte) []int64 {
+	var usados []int64
+	for i, b := range bitmap {
+		if b == FS.BitmapOcupado {
+			usados = append(usados, int64(i))
+		}
+	}
+	return usados
+}
+
+// clasificarBloques recorre el árbol desde la raíz y determina el tipo de
+// cada bloque alcanzable según el inodo que lo referencia
+func clasificarBloques(v *FS.Volumen) map[int64]string {
+	tipos := map[int64]string{}
+	visitados := map[int64]bool{}
+
+	var recorrer func(n int64)
+	recorrer = func(n int64) {
+		if visitados[n] || n < 0 || n >= v.Super.S_inodes_count {
+			return
+		}
+		visitados[n] = true
+
+		inodo, err := v.ReadInode(n)
+		if err != nil {
+			return
+		}
+		tipo := bloqueArchivo
+		if inodo.I_type == FS.TipoCarpeta {
+			tipo = bloqueCarpeta
+		}
+		if datos, err := v.BloquesDeInodo(inodo); err == nil {
+			for _, b := range datos {
+				tipos[b] = tipo
+			}
+		}
+		if apuntadores, err := v.BloquesApuntadoresDeInodo(inodo); err == nil {
+			for _, b := range apuntadores {
+				tipos[b] = bloqueApuntadores
+			}
+		}
+
+		if inodo.I_type != FS.TipoCarpeta {
+			return
+		}
+		entradas, err := v.Entradas(inodo)
+		if err != nil {
+			return
+		}
+		for _, e := range entradas {
+			if e.Nombre != "." && e.Nombre != ".." {
+				recorrer(e.Inodo)
+			}
+		}
+	}
+
+	recorrer(0)
+	return tipos
+}
@@ -0,0 +1,431 @@
+package Comandos
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Rasterización de los dibujos de reporte sin dependencias externas: las
+// figuras se pintan sobre un image.RGBA con una fuente de mapa de bits 5x7 y
+// la imagen se codifica en PNG, JPG o dentro de un PDF mínimo.
+
+const (
+	// escalaImagen son los píxeles por unidad del dibujo
+	escalaImagen = 2
+	// maxPixeles limita el tamaño de la imagen: si el dibujo no cabe a
+	// escalaImagen se rasteriza a un píxel por unidad, y si tampoco cabe así
+	// se rechaza
+	maxPixeles = 25_000_000
+	// anchoGlifo es el avance horizontal de cada carácter en píxeles de fuente
+	anchoGlifo = 6
+	// altoGlifo es la altura de cada carácter en píxeles de fuente
+	altoGlifo = 7
+)
+
+// lienzo es la imagen donde se pintan las figuras de un dibujo
+type lienzo struct {
+	img    *image.RGBA
+	escala int
+}
+
+// aImagen rasteriza el dibujo con fondo blanco
+func (d dibujo) aImagen() (*image.RGBA, error) {
+	if d.Ancho*d.Alto > maxPixeles {
+		return nil, fmt.Errorf("el reporte mide %dx%d y excede el máximo de %d píxeles para imágenes; use el formato .svg o .dot",
+			d.Ancho, d.Alto, maxPixeles)
+	}
+	escala := escalaImagen
+	if d.Ancho*d.Alto*escala*escala > maxPixeles {
+		escala = 1
+	}
+	img := image.NewRGBA(image.Rect(0, 0, d.Ancho*escala, d.Alto*escala))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+
+	l := &lienzo{img: img, escala: escala}
+	for _, f := range d.figuras {
+		f.rasterizar(l)
+	}
+	return img, nil
+}
+
+// aPNG codifica el dibujo en PNG
+func (d dibujo) aPNG() ([]byte, error) {
+	img, err := d.aImagen()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// aJPG codifica el dibujo en JPEG
+func (d dibujo) aJPG() ([]byte, error) {
+	img, err := d.aImagen()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 92}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// aPDF escribe un PDF de una página del tamaño del dibujo (una unidad por
+// punto) con la imagen rasterizada comprimida con FlateDecode
+func (d dibujo) aPDF() ([]byte, error) {
+	img, err := d.aImagen()
+	if err != nil {
+		return nil, err
+	}
+	ancho, alto := img.Bounds().Dx(), img.Bounds().Dy()
+
+	// Píxeles en RGB sin canal alfa, comprimidos con zlib
+	var datos bytes.Buffer
+	z := zlib.NewWriter(&datos)
+	fila := make([]byte, ancho*3)
+	for y := 0; y < alto; y++ {
+		for x := 0; x < ancho; x++ {
+			c := img.RGBAAt(x, y)
+			fila[x*3], fila[x*3+1], fila[x*3+2] = c.R, c.G, c.B
+		}
+		if _, err := z.Write(fila); err != nil {
+			return nil, err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+
+	contenido := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im0 Do Q\n", d.Ancho, d.Alto)
+	objetos := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im0 4 0 R >> >> /Contents 5 0 R >>",
+			d.Ancho, d.Alto),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n",
+			ancho, alto, datos.Len()) + datos.String() + "\nendstream",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(contenido), contenido),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	posiciones := make([]int, len(objetos))
+	for i, obj := range objetos {
+		posiciones[i] = pdf.Len()
+		pdf.WriteString(fmt.Sprintf("%d 0 obj\n%s\nendobj\n", i+1, obj))
+	}
+	inicioXref := pdf.Len()
+	pdf.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", len(objetos)+1))
+	for _, p := range posiciones {
+		pdf.WriteString(fmt.Sprintf("%010d 00000 n \n", p))
+	}
+	pdf.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objetos)+1, inicioXref))
+	return pdf.Bytes(), nil
+}
+
+// colorReporte interpreta los colores usados en los dibujos: "white" y
+// hexadecimales #rgb o #rrggbb. Cualquier otro valor se pinta en negro.
+func colorReporte(s string) color.RGBA {
+	negro := color.RGBA{A: 255}
+	if s == "white" {
+		return color.RGBA{255, 255, 255, 255}
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || hex == s {
+		return negro
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return negro
+	}
+	return color.RGBA{uint8(v >> 16), uint8(v >> 8), uint8(v), 255}
+}
+
+// pintar rellena el rectángulo de píxeles indicado
+func (l *lienzo) pintar(x0, y0, x1, y1 int, c color.RGBA) {
+	draw.Draw(l.img, image.Rect(x0, y0, x1, y1), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// grosor es el grosor en píxeles de bordes y líneas
+func (l *lienzo) grosor() int {
+	return max(1, l.escala/2)
+}
+
+func (r figRect) rasterizar(l *lienzo) {
+	e := l.escala
+	x0, y0, x1, y1 := r.x*e, r.y*e, (r.x+r.ancho)*e, (r.y+r.alto)*e
+	l.pintar(x0, y0, x1, y1, colorReporte(r.relleno))
+	if r.borde == "" {
+		return
+	}
+	c, g := colorReporte(r.borde), l.grosor()
+	l.pintar(x0, y0, x1, y0+g, c)
+	l.pintar(x0, y1-g, x1, y1, c)
+	l.pintar(x0, y0, x0+g, y1, c)
+	l.pintar(x1-g, y0, x1, y1, c)
+}
+
+func (f figLinea) rasterizar(l *lienzo) {
+	e := l.escala
+	c, g := colorReporte(f.color), l.grosor()
+	x0, y0, x1, y1 := f.x1*e, f.y1*e, f.x2*e, f.y2*e
+
+	// Bresenham
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		l.pintar(x0, y0, x0+g, y0+g, c)
+		if x0 == x1 && y0 == y1 {
+			break
+		}
+		if e2 := 2 * err; e2 >= dy {
+			err += dy
+			x0 += sx
+		} else {
+			err += dx
+			y0 += sy
+		}
+	}
+
+	if f.flecha {
+		l.punta(float64(f.x1*e), float64(f.y1*e), float64(f.x2*e), float64(f.y2*e), c)
+	}
+}
+
+// punta rellena la punta de flecha (10 x 7 unidades, como el marcador del
+// SVG) en el extremo (x2, y2) de la línea
+func (l *lienzo) punta(x1, y1, x2, y2 float64, c color.RGBA) {
+	dx, dy := x2-x1, y2-y1
+	largo := math.Hypot(dx, dy)
+	if largo == 0 {
+		return
+	}
+	ux, uy := dx/largo, dy/largo
+	e := float64(l.escala)
+	bx, by := x2-ux*10*e, y2-uy*10*e
+	ax, ay := bx-uy*3.5*e, by+ux*3.5*e
+	cx, cy := bx+uy*3.5*e, by-ux*3.5*e
+
+	minX, maxX := int(min(ax, cx, x2)), int(max(ax, cx, x2))+1
+	minY, maxY := int(min(ay, cy, y2)), int(max(ay, cy, y2))+1
+	lado := func(px, py, qx, qy, rx, ry float64) float64 { return (qx-px)*(ry-py) - (qy-py)*(rx-px) }
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			d1, d2, d3 := lado(ax, ay, cx, cy, px, py), lado(cx, cy, x2, y2, px, py), lado(x2, y2, ax, ay, px, py)
+			if (d1 >= 0 && d2 >= 0 && d3 >= 0) || (d1 <= 0 && d2 <= 0 && d3 <= 0) {
+				l.img.SetRGBA(x, y, c)
+			}
+		}
+	}
+}
+
+func (t figTexto) rasterizar(l *lienzo) {
+	e := l.escala
+	runas := []rune(t.contenido)
+	x := t.x * e
+	if t.centrado {
+		x -= (len(runas)*anchoGlifo - 1) * e / 2
+	}
+	y := (t.y - altoGlifo) * e
+	c := colorReporte(t.color)
+
+	for _, r := range runas {
+		g, ok := glifo(r)
+		if !ok {
+			continue
+		}
+		for fila := 0; fila < altoGlifo; fila++ {
+			for col := 0; col < 5; col++ {
+				if g[fila]&(0x10>>col) == 0 {
+					continue
+				}
+				px, py := x+col*e, y+fila*e
+				l.pintar(px, py, px+e, py+e, c)
+				if t.negrita {
+					l.pintar(px+e, py, px+e+l.grosor(), py+e, c)
+				}
+			}
+		}
+		x += anchoGlifo * e
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// glifo retorna las 7 filas de 5 bits de la runa; las letras acentuadas sin
+// glifo propio usan la letra base y los símbolos desconocidos, '?'. El
+// selector de variación de los emoji no ocupa espacio.
+func glifo(r rune) ([altoGlifo]byte, bool) {
+	if r == '\uFE0F' {
+		return [altoGlifo]byte{}, false
+	}
+	if base, ok := letrasBase[r]; ok {
+		r = base
+	}
+	g, ok := glifos[r]
+	if !ok {
+		g = glifos['?']
+	}
+	return g, true
+}
+
+// glifos es fuente5x7 convertida a bits, con el bit 4 como columna izquierda
+var glifos = cargarFuente(fuente5x7)
+
+// cargarFuente convierte los glifos dibujados con '#' y '.' a filas de bits
+func cargarFuente(fuente map[rune]string) map[rune][altoGlifo]byte {
+	salida := make(map[rune][altoGlifo]byte, len(fuente))
+	for r, dibujoGlifo := range fuente {
+		var g [altoGlifo]byte
+		for i, fila := range strings.Split(dibujoGlifo, "|") {
+			for j, p := range fila {
+				if p == '#' {
+					g[i] |= 0x10 >> j
+				}
+			}
+		}
+		salida[r] = g
+	}
+	return salida
+}
+
+// letrasBase asocia los caracteres sin glifo propio con el que se dibuja
+var letrasBase = map[rune]rune{
+	'Á': 'A', 'É': 'E', 'Í': 'I', 'Ó': 'O', 'Ú': 'U', 'Ñ': 'N', 'Ü': 'U',
+	'ü': 'u', '→': '>', '⚠': '!', '\t': ' ',
+}
+
+// fuente5x7 es una fuente de mapa de bits de 5x7 píxeles para ASCII y las
+// vocales acentuadas y ñ minúsculas. Cada glifo son 7 filas separadas por '|'.
+var fuente5x7 = map[rune]string{
+	' ':  ".....|.....|.....|.....|.....|.....|.....",
+	'!':  "..#..|..#..|..#..|..#..|..#..|.....|..#..",
+	'"':  ".#.#.|.#.#.|.....|.....|.....|.....|.....",
+	'#':  ".#.#.|.#.#.|#####|.#.#.|#####|.#.#.|.#.#.",
+	'$':  "..#..|.####|#.#..|.###.|..#.#|####.|..#..",
+	'%':  "##...|##..#|...#.|..#..|.#...|#..##|...##",
+	'&':  ".##..|#..#.|#.#..|.#...|#.#.#|#..#.|.##.#",
+	'\'': "..#..|..#..|.....|.....|.....|.....|.....",
+	'(':  "...#.|..#..|.#...|.#...|.#...|..#..|...#.",
+	')':  ".#...|..#..|...#.|...#.|...#.|..#..|.#...",
+	'*':  ".....|..#..|#.#.#|.###.|#.#.#|..#..|.....",
+	'+':  ".....|..#..|..#..|#####|..#..|..#..|.....",
+	',':  ".....|.....|.....|.....|.##..|..#..|.#...",
+	'-':  ".....|.....|.....|#####|.....|.....|.....",
+	'.':  ".....|.....|.....|.....|.....|.##..|.##..",
+	'/':  ".....|....#|...#.|..#..|.#...|#....|.....",
+	'0':  ".###.|#...#|#..##|#.#.#|##..#|#...#|.###.",
+	'1':  "..#..|.##..|..#..|..#..|..#..|..#..|.###.",
+	'2':  ".###.|#...#|....#|...#.|..#..|.#...|#####",
+	'3':  "#####|...#.|..#..|...#.|....#|#...#|.###.",
+	'4':  "...#.|..##.|.#.#.|#..#.|#####|...#.|...#.",
+	'5':  "#####|#....|####.|....#|....#|#...#|.###.",
+	'6':  "..##.|.#...|#....|####.|#...#|#...#|.###.",
+	'7':  "#####|....#|...#.|..#..|.#...|.#...|.#...",
+	'8':  ".###.|#...#|#...#|.###.|#...#|#...#|.###.",
+	'9':  ".###.|#...#|#...#|.####|....#|...#.|.##..",
+	':':  ".....|.##..|.##..|.....|.##..|.##..|.....",
+	';':  ".....|.##..|.##..|.....|.##..|..#..|.#...",
+	'<':  "...#.|..#..|.#...|#....|.#...|..#..|...#.",
+	'=':  ".....|.....|#####|.....|#####|.....|.....",
+	'>':  ".#...|..#..|...#.|....#|...#.|..#..|.#...",
+	'?':  ".###.|#...#|....#|...#.|..#..|.....|..#..",
+	'@':  ".###.|#...#|....#|.##.#|#.#.#|#.#.#|.###.",
+	'A':  ".###.|#...#|#...#|#####|#...#|#...#|#...#",
+	'B':  "####.|#...#|#...#|####.|#...#|#...#|####.",
+	'C':  ".###.|#...#|#....|#....|#....|#...#|.###.",
+	'D':  "###..|#..#.|#...#|#...#|#...#|#..#.|###..",
+	'E':  "#####|#....|#....|####.|#....|#....|#####",
+	'F':  "#####|#....|#....|####.|#....|#....|#....",
+	'G':  ".###.|#...#|#....|#.###|#...#|#...#|.####",
+	'H':  "#...#|#...#|#...#|#####|#...#|#...#|#...#",
+	'I':  ".###.|..#..|..#..|..#..|..#..|..#..|.###.",
+	'J':  "..###|...#.|...#.|...#.|...#.|#..#.|.##..",
+	'K':  "#...#|#..#.|#.#..|##...|#.#..|#..#.|#...#",
+	'L':  "#....|#....|#....|#....|#....|#....|#####",
+	'M':  "#...#|##.##|#.#.#|#.#.#|#...#|#...#|#...#",
+	'N':  "#...#|#...#|##..#|#.#.#|#..##|#...#|#...#",
+	'O':  ".###.|#...#|#...#|#...#|#...#|#...#|.###.",
+	'P':  "####.|#...#|#...#|####.|#....|#....|#....",
+	'Q':  ".###.|#...#|#...#|#...#|#.#.#|#..#.|.##.#",
+	'R':  "####.|#...#|#...#|####.|#.#..|#..#.|#...#",
+	'S':  ".####|#....|#....|.###.|....#|....#|####.",
+	'T':  "#####|..#..|..#..|..#..|..#..|..#..|..#..",
+	'U':  "#...#|#...#|#...#|#...#|#...#|#...#|.###.",
+	'V':  "#...#|#...#|#...#|#...#|#...#|.#.#.|..#..",
+	'W':  "#...#|#...#|#...#|#.#.#|#.#.#|#.#.#|.#.#.",
+	'X':  "#...#|#...#|.#.#.|..#..|.#.#.|#...#|#...#",
+	'Y':  "#...#|#...#|.#.#.|..#..|..#..|..#..|..#..",
+	'Z':  "#####|....#|...#.|..#..|.#...|#....|#####",
+	'[':  ".###.|.#...|.#...|.#...|.#...|.#...|.###.",
+	'\\': ".....|#....|.#...|..#..|...#.|....#|.....",
+	']':  ".###.|...#.|...#.|...#.|...#.|...#.|.###.",
+	'^':  "..#..|.#.#.|#...#|.....|.....|.....|.....",
+	'_':  ".....|.....|.....|.....|.....|.....|#####",
+	'`':  ".#...|..#..|.....|.....|.....|.....|.....",
+	'a':  ".....|.....|.###.|....#|.####|#...#|.####",
+	'b':  "#....|#....|#.##.|##..#|#...#|#...#|####.",
+	'c':  ".....|.....|.###.|#....|#....|#...#|.###.",
+	'd':  "....#|....#|.##.#|#..##|#...#|#...#|.####",
+	'e':  ".....|.....|.###.|#...#|#####|#....|.###.",
+	'f':  "..##.|.#..#|.#...|###..|.#...|.#...|.#...",
+	'g':  ".....|.####|#...#|#...#|.####|....#|.###.",
+	'h':  "#....|#....|#.##.|##..#|#...#|#...#|#...#",
+	'i':  "..#..|.....|.##..|..#..|..#..|..#..|.###.",
+	'j':  "...#.|.....|..##.|...#.|...#.|#..#.|.##..",
+	'k':  "#....|#....|#..#.|#.#..|##...|#.#..|#..#.",
+	'l':  ".##..|..#..|..#..|..#..|..#..|..#..|.###.",
+	'm':  ".....|.....|##.#.|#.#.#|#.#.#|#...#|#...#",
+	'n':  ".....|.....|#.##.|##..#|#...#|#...#|#...#",
+	'o':  ".....|.....|.###.|#...#|#...#|#...#|.###.",
+	'p':  ".....|.....|####.|#...#|####.|#....|#....",
+	'q':  ".....|.....|.##.#|#..##|.####|....#|....#",
+	'r':  ".....|.....|#.##.|##..#|#....|#....|#....",
+	's':  ".....|.....|.###.|#....|.###.|....#|####.",
+	't':  ".#...|.#...|###..|.#...|.#...|.#..#|..##.",
+	'u':  ".....|.....|#...#|#...#|#...#|#..##|.##.#",
+	'v':  ".....|.....|#...#|#...#|#...#|.#.#.|..#..",
+	'w':  ".....|.....|#...#|#...#|#.#.#|#.#.#|.#.#.",
+	'x':  ".....|.....|#...#|.#.#.|..#..|.#.#.|#...#",
+	'y':  ".....|.....|#...#|#...#|.####|....#|.###.",
+	'z':  ".....|.....|#####|...#.|..#..|.#...|#####",
+	'{':  "...#.|..#..|..#..|.#...|..#..|..#..|...#.",
+	'|':  "..#..|..#..|..#..|..#..|..#..|..#..|..#..",
+	'}':  ".#...|..#..|..#..|...#.|..#..|..#..|.#...",
+	'~':  ".....|.....|.#...|#.#.#|...#.|.....|.....",
+	'á':  "...#.|..#..|.###.|....#|.####|#...#|.####",
+	'é':  "...#.|..#..|.###.|#...#|#####|#....|.###.",
+	'í':  "...#.|..#..|.....|.##..|..#..|..#..|.###.",
+	'ó':  "...#.|..#..|.###.|#...#|#...#|#...#|.###.",
+	'ú':  "...#.|..#..|#...#|#...#|#...#|#..##|.##.#",
+	'ñ':  ".##.#|#..#.|#.##.|##..#|#...#|#...#|#...#",
+}
@@ -0,0 +1,961 @@
+package Comandos
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"os/exec"
+	"strings"
+)
+
+// quitarControles reemplaza los bytes de control (menores a 0x20, salvo
+// salto de línea y tabulación) por su forma \xNN. Los nombres y contenidos
+// del disco pueden traerlos y no son válidos en XML ni en Graphviz.
+func quitarControles(texto string) string {
+	var sb strings.Builder
+	for i := 0; i < len(texto); i++ {
+		c := texto[i]
+		if c < 0x20 && c != '\n' && c != '\t' {
+			sb.WriteString(fmt.Sprintf("\\x%02x", c))
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+// escaparMarcado prepara un texto para SVG, HTML o las etiquetas HTML de
+// Graphviz
+func escaparMarcado(texto string) string {
+	return html.EscapeString(quitarControles(texto))
+}
+
+// modeloReporte es el resultado intermedio de un reporte. Cada modelo sabe
+// representarse en los formatos de texto y como dibujo, que se exporta a SVG
+// o se rasteriza a PNG, JPG y PDF; JSON se obtiene de sus campos.
+type modeloReporte interface {
+	aTexto() string
+	aMarkdown() string
+	aHTML() string
+	aDot() string
+	aSVG() string
+	aDibujo() dibujo
+}
+
+// formatosReporte asocia cada extensión de -path con su renderizador
+var formatosReporte = map[string]func(modeloReporte) ([]byte, error){
+	".txt":  func(m modeloReporte) ([]byte, error) { return []byte(m.aTexto()), nil },
+	".md":   func(m modeloReporte) ([]byte, error) { return []byte(m.aMarkdown()), nil },
+	".html": func(m modeloReporte) ([]byte, error) { return []byte(m.aHTML()), nil },
+	".dot":  func(m modeloReporte) ([]byte, error) { return []byte(m.aDot()), nil },
+	".svg":  func(m modeloReporte) ([]byte, error) { return []byte(m.aSVG()), nil },
+	".json": aJSON,
+	".png":  func(m modeloReporte) ([]byte, error) { return m.aDibujo().aPNG() },
+	".jpg":  func(m modeloReporte) ([]byte, error) { return m.aDibujo().aJPG() },
+	".jpeg": func(m modeloReporte) ([]byte, error) { return m.aDibujo().aJPG() },
+	".pdf":  func(m modeloReporte) ([]byte, error) { return m.aDibujo().aPDF() },
+}
+
+// formatosTodos son las extensiones que admiten los reportes de tabla, barra y grafo
+var formatosTodos = []string{".dot", ".svg", ".png", ".jpg", ".jpeg", ".pdf", ".html", ".md", ".json", ".txt"}
+
+// renderizarReporte convierte el modelo al formato de la extensión indicada
+func renderizarReporte(modelo modeloReporte, extension string) ([]byte, error) {
+	renderizar, ok := formatosReporte[strings.ToLower(extension)]
+	if !ok {
+		return nil, fmt.Errorf("extensión no soportada: '%s'", extension)
+	}
+	return renderizar(modelo)
+}
+
+// aJSON serializa el modelo con sus campos
+func aJSON(modelo modeloReporte) ([]byte, error) {
+	datos, err := json.MarshalIndent(modelo, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(datos, '\n'), nil
+}
+
+// textoReporte es el modelo de los reportes de texto libre, como los bitmaps
+// o el contenido de un archivo. Sin título, el texto se escribe tal cual.
+type textoReporte struct {
+	Titulo    string `json:"titulo,omitempty"`
+	Contenido string `json:"contenido"`
+}
+
+// aTexto retorna el título seguido del contenido
+func (t textoReporte) aTexto() string {
+	if t.Titulo == "" {
+		return t.Contenido
+	}
+	return t.Titulo + "\n\n" + t.Contenido
+}
+
+// aMarkdown muestra el contenido como bloque de código
+func (t textoReporte) aMarkdown() string {
+	return "# " + t.Titulo + "\n\n```\n" + strings.TrimRight(t.Contenido, "\n") + "\n```\n"
+}
+
+// aHTML muestra el contenido preformateado
+func (t textoReporte) aHTML() string {
+	return "<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>" + escaparMarcado(t.Titulo) +
+		"</title>\n</head>\n<body>\n<h2>" + escaparMarcado(t.Titulo) + "</h2>\n<pre>" +
+		escaparMarcado(t.Contenido) + "</pre>\n</body>\n</html>\n"
+}
+
+// aDot muestra el contenido como la etiqueta de un único nodo
+func (t textoReporte) aDot() string {
+	etiqueta := strings.ReplaceAll(strings.ReplaceAll(quitarControles(t.aTexto()), "\\", "\\\\"), "\"", "\\\"")
+	etiqueta = strings.ReplaceAll(etiqueta, "\n", "\\l")
+	return "digraph G {\n  node [shape=box fontname=\"Courier\"];\n  texto [label=\"" + etiqueta + "\"];\n}\n"
+}
+
+// aSVG dibuja el contenido línea por línea con fuente monoespaciada
+func (t textoReporte) aSVG() string { return t.aDibujo().aSVG() }
+
+// aDibujo distribuye el contenido línea por línea con fuente monoespaciada
+func (t textoReporte) aDibujo() dibujo {
+	lineas := strings.Split(strings.TrimRight(t.aTexto(), "\n"), "\n")
+	ancho := 0
+	for _, l := range lineas {
+		if n := len([]rune(l)); n > ancho {
+			ancho = n
+		}
+	}
+
+	d := dibujo{Ancho: ancho*8 + 20, Alto: len(lineas)*16 + 20}
+	for i, l := range lineas {
+		d.texto(figTexto{x: 10, y: 24 + i*16, contenido: l, tamaño: 13, mono: true})
+	}
+	return d
+}
+
+// tablaReporte es el modelo de los reportes tabulares: un título y una lista
+// de secciones con filas de campo/valor o de varias columnas
+type tablaReporte struct {
+	Titulo    string           `json:"titulo"`
+	Secciones []seccionReporte `json:"secciones"`
+}
+
+// seccionReporte agrupa filas bajo un encabezado con color. Columnas es
+// opcional y se muestra como fila de encabezados.
+type seccionReporte struct {
+	Titulo   string     `json:"titulo"`
+	Color    string     `json:"color"`
+	Columnas []string   `json:"columnas,omitempty"`
+	Filas    [][]string `json:"filas"`
+}
+
+// agregarFila agrega un par campo/valor a la sección
+func (s *seccionReporte) agregarFila(campo string, valor interface{}) {
+	s.Filas = append(s.Filas, []string{campo, fmt.Sprint(valor)})
+}
+
+// agregarRegistro agrega una fila con un valor por columna
+func (s *seccionReporte) agregarRegistro(valores ...interface{}) {
+	fila := make([]string, len(valores))
+	for i, v := range valores {
+		fila[i] = fmt.Sprint(v)
+	}
+	s.Filas = append(s.Filas, fila)
+}
+
+// columnas retorna la cantidad de columnas de la tabla (al menos 2)
+func (t tablaReporte) columnas() int {
+	n := 2
+	for _, seccion := range t.Secciones {
+		if len(seccion.Columnas) > n {
+			n = len(seccion.Columnas)
+		}
+		for _, fila := range seccion.Filas {
+			if len(fila) > n {
+				n = len(fila)
+			}
+		}
+	}
+	return n
+}
+
+// celdas completa una fila con celdas vacías hasta n columnas
+func celdas(fila []string, n int) []string {
+	for len(fila) < n {
+		fila = append(fila, "")
+	}
+	return fila
+}
+
+// aTexto genera el reporte como texto plano con columnas alineadas. Los
+// valores de varias líneas continúan bajo su columna.
+func (tabla tablaReporte) aTexto() string {
+	var sb strings.Builder
+	sb.WriteString(tabla.Titulo + "\n")
+	sb.WriteString(strings.Repeat("=", len([]rune(tabla.Titulo))) + "\n")
+
+	for _, seccion := range tabla.Secciones {
+		filas := seccion.Filas
+		if len(seccion.Columnas) > 0 {
+			filas = append([][]string{seccion.Columnas}, filas...)
+		}
+
+		// Ancho de cada columna menos la última, que no se rellena
+		var anchos []int
+		for _, fila := range filas {
+			for i := 0; i < len(fila)-1; i++ {
+				if i >= len(anchos) {
+					anchos = append(anchos, 0)
+				}
+				if n := len([]rune(fila[i])); n > anchos[i] {
+					anchos[i] = n
+				}
+			}
+		}
+
+		sb.WriteString("\n" + seccion.Titulo + "\n")
+		sb.WriteString(strings.Repeat("-", len([]rune(seccion.Titulo))) + "\n")
+		for _, fila := range filas {
+			sangria := 0
+			for i, celda := range fila {
+				if i == len(fila)-1 {
+					celda = strings.ReplaceAll(celda, "\n", "\n"+strings.Repeat(" ", sangria))
+					sb.WriteString(celda)
+					break
+				}
+				sb.WriteString(celda + strings.Repeat(" ", anchos[i]-len([]rune(celda))+2))
+				sangria += anchos[i] + 2
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// celdaMarkdown escapa una celda de tabla de Markdown
+func celdaMarkdown(texto string) string {
+	texto = strings.ReplaceAll(texto, "|", "\\|")
+	return strings.ReplaceAll(texto, "\n", "<br>")
+}
+
+// aMarkdown genera una tabla de Markdown por sección
+func (tabla tablaReporte) aMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# " + tabla.Titulo + "\n")
+	for _, seccion := range tabla.Secciones {
+		n := 2
+		for _, fila := range seccion.Filas {
+			if len(fila) > n {
+				n = len(fila)
+			}
+		}
+		if len(seccion.Columnas) > n {
+			n = len(seccion.Columnas)
+		}
+		encabezados := seccion.Columnas
+		if len(encabezados) == 0 {
+			encabezados = []string{"Campo", "Valor"}
+		}
+
+		sb.WriteString("\n## " + seccion.Titulo + "\n\n")
+		sb.WriteString("|")
+		for _, c := range celdas(encabezados, n) {
+			sb.WriteString(" " + celdaMarkdown(c) + " |")
+		}
+		sb.WriteString("\n|" + strings.Repeat(" --- |", n) + "\n")
+		for _, fila := range seccion.Filas {
+			sb.WriteString("|")
+			for _, c := range celdas(fila, n) {
+				sb.WriteString(" " + celdaMarkdown(c) + " |")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// aHTML genera el reporte como una tabla HTML
+func (tabla tablaReporte) aHTML() string {
+	n := tabla.columnas()
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>" + escaparMarcado(tabla.Titulo) + "</title>\n")
+	sb.WriteString("<style>body{font-family:sans-serif}table{border-collapse:collapse}td,th{border:1px solid #333;padding:4px 10px;text-align:left;white-space:pre-wrap}</style>\n")
+	sb.WriteString("</head>\n<body>\n<table>\n")
+	sb.WriteString(fmt.Sprintf("<tr><th colspan=\"%d\" style=\"background:#4a235a;color:white\">%s</th></tr>\n", n, escaparMarcado(tabla.Titulo)))
+	for _, seccion := range tabla.Secciones {
+		sb.WriteString(fmt.Sprintf("<tr><th colspan=\"%d\" style=\"background:%s;color:white\">%s</th></tr>\n",
+			n, seccion.Color, escaparMarcado(seccion.Titulo)))
+		if len(seccion.Columnas) > 0 {
+			sb.WriteString("<tr>")
+			for _, c := range celdas(seccion.Columnas, n) {
+				sb.WriteString("<th>" + escaparMarcado(c) + "</th>")
+			}
+			sb.WriteString("</tr>\n")
+		}
+		for _, fila := range seccion.Filas {
+			sb.WriteString("<tr>")
+			for _, c := range celdas(fila, n) {
+				sb.WriteString("<td>" + escaparMarcado(c) + "</td>")
+			}
+			sb.WriteString("</tr>\n")
+		}
+	}
+	sb.WriteString("</table>\n</body>\n</html>\n")
+	return sb.String()
+}
+
+// aDot genera el reporte como un nodo de Graphviz con etiqueta HTML
+func (tabla tablaReporte) aDot() string {
+	n := tabla.columnas()
+	celda := func(texto, atributos string) string {
+		return fmt.Sprintf("<td%s>%s</td>", atributos, strings.ReplaceAll(escaparMarcado(texto), "\n", "<br align=\"left\"/>"))
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	sb.WriteString("  node [shape=plaintext fontname=\"Helvetica\"];\n")
+	sb.WriteString("  tabla [label=<\n")
+	sb.WriteString("    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n")
+	sb.WriteString(fmt.Sprintf("      <tr><td colspan=\"%d\" bgcolor=\"#4a235a\"><font color=\"white\"><b>%s</b></font></td></tr>\n",
+		n, escaparMarcado(tabla.Titulo)))
+	for _, seccion := range tabla.Secciones {
+		sb.WriteString(fmt.Sprintf("      <tr><td colspan=\"%d\" bgcolor=\"%s\"><font color=\"white\"><b>%s</b></font></td></tr>\n",
+			n, seccion.Color, escaparMarcado(seccion.Titulo)))
+		if len(seccion.Columnas) > 0 {
+			sb.WriteString("      <tr>")
+			for _, c := range celdas(seccion.Columnas, n) {
+				sb.WriteString(celda(c, " bgcolor=\"#d5d8dc\""))
+			}
+			sb.WriteString("</tr>\n")
+		}
+		for _, fila := range seccion.Filas {
+			sb.WriteString("      <tr>")
+			for _, c := range celdas(fila, n) {
+				sb.WriteString(celda(c, " align=\"left\" balign=\"left\""))
+			}
+			sb.WriteString("</tr>\n")
+		}
+	}
+	sb.WriteString("    </table>\n  >];\n}\n")
+	return sb.String()
+}
+
+// aSVG dibuja la tabla con encabezados de color por sección
+func (tabla tablaReporte) aSVG() string { return tabla.aDibujo().aSVG() }
+
+// aDibujo distribuye la tabla con encabezados de color por sección
+func (tabla tablaReporte) aDibujo() dibujo {
+	const (
+		alto   = 24
+		minimo = 120
+		margen = 10
+	)
+	n := tabla.columnas()
+	texto := func(s string) string { return strings.ReplaceAll(s, "\n", " ") }
+
+	// Ancho de cada columna según el texto más largo
+	anchos := make([]int, n)
+	for i := range anchos {
+		anchos[i] = minimo
+	}
+	filas := 1
+	for _, seccion := range tabla.Secciones {
+		filas++
+		todas := seccion.Filas
+		if len(seccion.Columnas) > 0 {
+			todas = append([][]string{seccion.Columnas}, todas...)
+		}
+		filas += len(todas)
+		for _, fila := range todas {
+			for i, c := range fila {
+				if w := len([]rune(texto(c)))*8 + 20; w > anchos[i] {
+					anchos[i] = w
+				}
+			}
+		}
+	}
+	ancho := 0
+	for _, a := range anchos {
+		ancho += a
+	}
+
+	d := dibujo{Ancho: ancho + 2*margen, Alto: filas*alto + 2*margen}
+	y := margen
+	encabezado := func(titulo, color string) {
+		d.rectangulo(margen, y, ancho, alto, color, "#333")
+		d.texto(figTexto{x: margen + 6, y: y + 16, contenido: titulo, color: "white", tamaño: 13, negrita: true})
+		y += alto
+	}
+	fila := func(valores []string, fondo string, negrita bool) {
+		x := margen
+		for i, c := range celdas(valores, n) {
+			d.rectangulo(x, y, anchos[i], alto, fondo, "#333")
+			d.texto(figTexto{x: x + 6, y: y + 16, contenido: texto(c), tamaño: 13, negrita: negrita})
+			x += anchos[i]
+		}
+		y += alto
+	}
+
+	encabezado(tabla.Titulo, "#4a235a")
+	for _, seccion := range tabla.Secciones {
+		encabezado(seccion.Titulo, seccion.Color)
+		if len(seccion.Columnas) > 0 {
+			fila(seccion.Columnas, "#d5d8dc", true)
+		}
+		for _, f := range seccion.Filas {
+			fila(f, "white", false)
+		}
+	}
+	return d
+}
+
+// barraReporte es el modelo de los reportes de distribución: segmentos
+// consecutivos de un total, con segmentos anidados (la extendida)
+type barraReporte struct {
+	Titulo    string            `json:"titulo"`
+	Total     int64             `json:"total"`
+	Segmentos []segmentoReporte `json:"segmentos"`
+}
+
+// segmentoReporte es una región de la barra con su posición y tamaño en bytes
+type segmentoReporte struct {
+	Nombre  string            `json:"nombre"`
+	Detalle string            `json:"detalle,omitempty"`
+	Inicio  int64             `json:"inicio"`
+	Tamaño  int64             `json:"tamaño"`
+	Color   string            `json:"color"`
+	Hijos   []segmentoReporte `json:"hijos,omitempty"`
+}
+
+// porcentaje retorna la proporción del segmento respecto al total
+func (b barraReporte) porcentaje(s segmentoReporte) float64 {
+	if b.Total <= 0 {
+		return 0
+	}
+	return float64(s.Tamaño) * 100 / float64(b.Total)
+}
+
+// aTexto lista los segmentos con su rango y porcentaje
+func (barra barraReporte) aTexto() string {
+	var sb strings.Builder
+	sb.WriteString(barra.Titulo + "\n")
+	sb.WriteString(strings.Repeat("=", len([]rune(barra.Titulo))) + "\n")
+	sb.WriteString(fmt.Sprintf("Tamaño total: %d bytes\n\n", barra.Total))
+
+	var escribir func(segmentos []segmentoReporte, sangria string)
+	escribir = func(segmentos []segmentoReporte, sangria string) {
+		for _, s := range segmentos {
+			nombre := s.Nombre
+			if s.Detalle != "" {
+				nombre += " (" + s.Detalle + ")"
+			}
+			sb.WriteString(fmt.Sprintf("%s%-*s %10d - %-10d %10d bytes %7.2f%%\n",
+				sangria, 32-len(sangria), nombre, s.Inicio, s.Inicio+s.Tamaño, s.Tamaño, barra.porcentaje(s)))
+			escribir(s.Hijos, sangria+"    ")
+		}
+	}
+	escribir(barra.Segmentos, "")
+	return sb.String()
+}
+
+// aMarkdown lista los segmentos en una tabla; los anidados llevan sangría
+func (barra barraReporte) aMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# " + barra.Titulo + "\n\n")
+	sb.WriteString(fmt.Sprintf("Tamaño total: %d bytes\n\n", barra.Total))
+	sb.WriteString("| Segmento | Inicio | Fin | Tamaño | % |\n| --- | --- | --- | --- | --- |\n")
+
+	var escribir func(segmentos []segmentoReporte, sangria string)
+	escribir = func(segmentos []segmentoReporte, sangria string) {
+		for _, s := range segmentos {
+			nombre := s.Nombre
+			if s.Detalle != "" {
+				nombre += " (" + s.Detalle + ")"
+			}
+			sb.WriteString(fmt.Sprintf("| %s%s | %d | %d | %d | %.2f%% |\n",
+				sangria, celdaMarkdown(nombre), s.Inicio, s.Inicio+s.Tamaño, s.Tamaño, barra.porcentaje(s)))
+			escribir(s.Hijos, sangria+"&nbsp;&nbsp;&nbsp;&nbsp;")
+		}
+	}
+	escribir(barra.Segmentos, "")
+	return sb.String()
+}
+
+// aHTML dibuja la barra con cajas flexibles de ancho proporcional
+func (barra barraReporte) aHTML() string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>" + escaparMarcado(barra.Titulo) + "</title>\n")
+	sb.WriteString("<style>body{font-family:sans-serif}.barra{display:flex;border:2px solid #333;min-height:90px}" +
+		".seg{border:1px solid #333;min-width:3px;overflow:hidden;font-size:12px;text-align:center;color:white;display:flex;flex-direction:column}" +
+		".seg>.barra{border:none;flex:1;min-height:60px}</style>\n")
+	sb.WriteString("</head>\n<body>\n<h2>" + escaparMarcado(barra.Titulo) + "</h2>\n")
+
+	var escribir func(segmentos []segmentoReporte)
+	escribir = func(segmentos []segmentoReporte) {
+		sb.WriteString("<div class=\"barra\">\n")
+		for _, s := range segmentos {
+			sb.WriteString(fmt.Sprintf("<div class=\"seg\" style=\"flex:0 0 %.4f%%;background:%s\" title=\"%d - %d\">",
+				barra.porcentaje(s)*100/porcentajePadre(barra, segmentos), s.Color, s.Inicio, s.Inicio+s.Tamaño))
+			sb.WriteString(fmt.Sprintf("<b>%s</b>", escaparMarcado(s.Nombre)))
+			if s.Detalle != "" {
+				sb.WriteString("<span>" + escaparMarcado(s.Detalle) + "</span>")
+			}
+			sb.WriteString(fmt.Sprintf("<span>%.2f%%</span>", barra.porcentaje(s)))
+			if len(s.Hijos) > 0 {
+				escribir(s.Hijos)
+			}
+			sb.WriteString("</div>\n")
+		}
+		sb.WriteString("</div>\n")
+	}
+	escribir(barra.Segmentos)
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// porcentajePadre retorna el porcentaje que ocupan juntos los segmentos, para
+// que los anchos de una barra anidada sean relativos a su contenedor
+func porcentajePadre(barra barraReporte, segmentos []segmentoReporte) float64 {
+	total := 0.0
+	for _, s := range segmentos {
+		total += barra.porcentaje(s)
+	}
+	if total == 0 {
+		return 1
+	}
+	return total
+}
+
+// aDot genera la barra como una tabla HTML de Graphviz en una sola fila
+func (barra barraReporte) aDot() string {
+	const anchoTotal = 1000
+
+	celda := func(s segmentoReporte) string {
+		texto := "<b>" + escaparMarcado(s.Nombre) + "</b>"
+		if s.Detalle != "" {
+			texto += "<br/>" + escaparMarcado(s.Detalle)
+		}
+		texto += fmt.Sprintf("<br/>%.2f%%", barra.porcentaje(s))
+		ancho := int(barra.porcentaje(s) * anchoTotal / 100)
+		if ancho < 40 {
+			ancho = 40
+		}
+		return fmt.Sprintf("<td bgcolor=\"%s\" width=\"%d\" height=\"80\"><font color=\"white\">%s</font></td>", s.Color, ancho, texto)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	sb.WriteString("  node [shape=plaintext fontname=\"Helvetica\"];\n")
+	sb.WriteString(fmt.Sprintf("  labelloc=\"t\";\n  label=\"%s\";\n", etiquetaDot(barra.Titulo)))
+	sb.WriteString("  disco [label=<\n    <table border=\"1\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n      <tr>\n")
+	for _, s := range barra.Segmentos {
+		if len(s.Hijos) == 0 {
+			sb.WriteString("        " + celda(s) + "\n")
+			continue
+		}
+		// La extendida contiene una tabla con su encabezado y sus hijos
+		sb.WriteString(fmt.Sprintf("        <td bgcolor=\"%s\"><table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"4\">\n", s.Color))
+		sb.WriteString(fmt.Sprintf("          <tr><td colspan=\"%d\"><font color=\"white\"><b>%s</b> %.2f%%</font></td></tr>\n          <tr>",
+			len(s.Hijos), escaparMarcado(s.Nombre), barra.porcentaje(s)))
+		for _, h := range s.Hijos {
+			sb.WriteString(celda(h))
+		}
+		sb.WriteString("</tr>\n        </table></td>\n")
+	}
+	sb.WriteString("      </tr>\n    </table>\n  >];\n}\n")
+	return sb.String()
+}
+
+// aSVG dibuja la barra del disco con los segmentos anidados
+func (barra barraReporte) aSVG() string { return barra.aDibujo().aSVG() }
+
+// aDibujo distribuye la barra con anchos proporcionales al tamaño de cada
+// segmento y un mínimo para que se lea el texto
+func (barra barraReporte) aDibujo() dibujo {
+	const (
+		anchoTotal = 1000
+		minimo     = 36
+		alto       = 110
+		margen     = 10
+		encabezado = 24
+	)
+
+	ancho := func(s segmentoReporte) int {
+		if len(s.Hijos) > 0 {
+			suma := 0
+			for _, h := range s.Hijos {
+				suma += int(max64(int64(barra.porcentaje(h)*anchoTotal/100), minimo))
+			}
+			return suma
+		}
+		return int(max64(int64(barra.porcentaje(s)*anchoTotal/100), minimo))
+	}
+
+	var d dibujo
+	d.texto(figTexto{x: margen, y: margen + 16, contenido: barra.Titulo, tamaño: 15, negrita: true})
+	texto := func(s segmentoReporte, x, y, w int) {
+		cx := x + w/2
+		d.texto(figTexto{x: cx, y: y, contenido: s.Nombre, color: "white", negrita: true, centrado: true})
+		if s.Detalle != "" {
+			d.texto(figTexto{x: cx, y: y + 16, contenido: s.Detalle, color: "white", centrado: true})
+		}
+		d.texto(figTexto{x: cx, y: y + 32, contenido: fmt.Sprintf("%.2f%%", barra.porcentaje(s)), color: "white", centrado: true})
+	}
+
+	x := margen
+	y := margen + encabezado
+	for _, s := range barra.Segmentos {
+		w := ancho(s)
+		d.rectangulo(x, y, w, alto, s.Color, "#333")
+		if len(s.Hijos) == 0 {
+			texto(s, x, y+40, w)
+			x += w
+			continue
+		}
+		d.texto(figTexto{x: x + 6, y: y + 16, contenido: fmt.Sprintf("%s %.2f%%", s.Nombre, barra.porcentaje(s)), color: "white", negrita: true})
+		hx := x
+		for _, h := range s.Hijos {
+			hw := ancho(h)
+			d.rectangulo(hx, y+encabezado, hw, alto-encabezado, h.Color, "#333")
+			texto(h, hx, y+encabezado+30, hw)
+			hx += hw
+		}
+		x += w
+	}
+
+	d.Ancho, d.Alto = x+margen, y+alto+margen
+	return d
+}
+
+// grafoReporte es el modelo de los reportes de estructuras enlazadas: nodos
+// con filas campo/valor y aristas dirigidas entre ellos
+type grafoReporte struct {
+	Titulo  string          `json:"titulo"`
+	Nodos   []nodoReporte   `json:"nodos"`
+	Aristas []aristaReporte `json:"aristas"`
+}
+
+// nodoReporte es una estructura del reporte (inodo, bloque, ...)
+type nodoReporte struct {
+	ID     string      `json:"id"`
+	Titulo string      `json:"titulo"`
+	Color  string      `json:"color"`
+	Filas  [][2]string `json:"filas"`
+}
+
+// aristaReporte enlaza dos nodos por su ID
+type aristaReporte struct {
+	Desde    string `json:"desde"`
+	Hasta    string `json:"hasta"`
+	Etiqueta string `json:"etiqueta,omitempty"`
+}
+
+// agregarFila agrega un par campo/valor al nodo
+func (n *nodoReporte) agregarFila(campo string, valor interface{}) {
+	n.Filas = append(n.Filas, [2]string{campo, fmt.Sprint(valor)})
+}
+
+// ejecutarGraphviz convierte un grafo DOT al formato indicado usando el
+// binario dot de Graphviz, si está instalado
+func ejecutarGraphviz(dot, formato string) ([]byte, error) {
+	ruta, err := exec.LookPath("dot")
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(ruta, "-T"+formato)
+	cmd.Stdin = strings.NewReader(dot)
+	return cmd.Output()
+}
+
+// comoTabla convierte cada nodo del grafo en una sección de tabla
+func (grafo grafoReporte) comoTabla() tablaReporte {
+	tabla := tablaReporte{Titulo: grafo.Titulo}
+	for _, n := range grafo.Nodos {
+		seccion := seccionReporte{Titulo: n.Titulo}
+		for _, fila := range n.Filas {
+			seccion.agregarFila(fila[0], fila[1])
+		}
+		tabla.Secciones = append(tabla.Secciones, seccion)
+	}
+	return tabla
+}
+
+// aTexto lista cada nodo con sus campos y luego los enlaces
+func (grafo grafoReporte) aTexto() string {
+	var sb strings.Builder
+	sb.WriteString(grafo.comoTabla().aTexto())
+	if len(grafo.Aristas) > 0 {
+		sb.WriteString("\nEnlaces\n-------\n")
+		titulos := titulosNodos(grafo)
+		for _, a := range grafo.Aristas {
+			sb.WriteString(fmt.Sprintf("%s -> %s", titulos[a.Desde], titulos[a.Hasta]))
+			if a.Etiqueta != "" {
+				sb.WriteString(" [" + a.Etiqueta + "]")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// aMarkdown genera una tabla por nodo y la lista de enlaces
+func (grafo grafoReporte) aMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString(grafo.comoTabla().aMarkdown())
+	if len(grafo.Aristas) > 0 {
+		sb.WriteString("\n## Enlaces\n\n")
+		titulos := titulosNodos(grafo)
+		for _, a := range grafo.Aristas {
+			sb.WriteString(fmt.Sprintf("- %s → %s", titulos[a.Desde], titulos[a.Hasta]))
+			if a.Etiqueta != "" {
+				sb.WriteString(" (" + a.Etiqueta + ")")
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// aHTML muestra cada nodo como una tabla y los enlaces como lista
+func (grafo grafoReporte) aHTML() string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString("<title>" + escaparMarcado(grafo.Titulo) + "</title>\n")
+	sb.WriteString("<style>body{font-family:sans-serif}table{border-collapse:collapse;display:inline-table;margin:6px;vertical-align:top}" +
+		"td,th{border:1px solid #333;padding:3px 8px;text-align:left;white-space:pre-wrap}</style>\n")
+	sb.WriteString("</head>\n<body>\n<h2>" + escaparMarcado(grafo.Titulo) + "</h2>\n")
+	for _, n := range grafo.Nodos {
+		sb.WriteString(fmt.Sprintf("<table id=\"%s\">\n<tr><th colspan=\"2\" style=\"background:%s;color:white\">%s</th></tr>\n",
+			escaparMarcado(n.ID), n.Color, escaparMarcado(n.Titulo)))
+		for _, fila := range n.Filas {
+			sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td></tr>\n", escaparMarcado(fila[0]), escaparMarcado(fila[1])))
+		}
+		sb.WriteString("</table>\n")
+	}
+	if len(grafo.Aristas) > 0 {
+		titulos := titulosNodos(grafo)
+		sb.WriteString("<h3>Enlaces</h3>\n<ul>\n")
+		for _, a := range grafo.Aristas {
+			texto := fmt.Sprintf("<a href=\"#%s\">%s</a> &rarr; <a href=\"#%s\">%s</a>",
+				escaparMarcado(a.Desde), escaparMarcado(titulos[a.Desde]),
+				escaparMarcado(a.Hasta), escaparMarcado(titulos[a.Hasta]))
+			if a.Etiqueta != "" {
+				texto += " (" + escaparMarcado(a.Etiqueta) + ")"
+			}
+			sb.WriteString("<li>" + texto + "</li>\n")
+		}
+		sb.WriteString("</ul>\n")
+	}
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// aDot genera el grafo de Graphviz con un nodo tabla por estructura
+func (grafo grafoReporte) aDot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=plaintext fontname=\"Helvetica\"];\n")
+	sb.WriteString(fmt.Sprintf("  labelloc=\"t\";\n  label=\"%s\";\n", etiquetaDot(grafo.Titulo)))
+	for _, n := range grafo.Nodos {
+		sb.WriteString(fmt.Sprintf("  %s [label=<\n    <table border=\"0\" cellborder=\"1\" cellspacing=\"0\" cellpadding=\"3\">\n", idDot(n.ID)))
+		sb.WriteString(fmt.Sprintf("      <tr><td colspan=\"2\" bgcolor=\"%s\"><font color=\"white\"><b>%s</b></font></td></tr>\n",
+			n.Color, escaparMarcado(n.Titulo)))
+		for _, fila := range n.Filas {
+			valor := strings.ReplaceAll(escaparMarcado(fila[1]), "\n", "<br/>")
+			sb.WriteString(fmt.Sprintf("      <tr><td align=\"left\">%s</td><td align=\"left\">%s</td></tr>\n",
+				escaparMarcado(fila[0]), valor))
+		}
+		sb.WriteString("    </table>\n  >];\n")
+	}
+	for _, a := range grafo.Aristas {
+		sb.WriteString(fmt.Sprintf("  %s -> %s", idDot(a.Desde), idDot(a.Hasta)))
+		if a.Etiqueta != "" {
+			sb.WriteString(fmt.Sprintf(" [label=\"%s\"]", etiquetaDot(a.Etiqueta)))
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// idDot convierte un ID a un identificador válido de Graphviz
+func idDot(id string) string {
+	return "\"" + etiquetaDot(id) + "\""
+}
+
+// etiquetaDot prepara un texto para ir entre comillas en Graphviz: escapa
+// los controles y la barra invertida y cambia las comillas por apóstrofos
+func etiquetaDot(texto string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(quitarControles(texto), "\\", "\\\\"), "\"", "'")
+}
+
+// titulosNodos indexa el título de cada nodo por su ID
+func titulosNodos(grafo grafoReporte) map[string]string {
+	titulos := make(map[string]string, len(grafo.Nodos))
+	for _, n := range grafo.Nodos {
+		titulos[n.ID] = n.Titulo
+	}
+	return titulos
+}
+
+// nivelesGrafo asigna a cada nodo una columna: las raíces (sin aristas de
+// entrada) van en la columna 0 y cada nodo una columna después del primero
+// que lo alcanza. Los nodos solo alcanzables por ciclos también parten de 0.
+func nivelesGrafo(grafo grafoReporte) map[string]int {
+	hijos := map[string][]string{}
+	entrantes := map[string]int{}
+	for _, a := range grafo.Aristas {
+		hijos[a.Desde] = append(hijos[a.Desde], a.Hasta)
+		entrantes[a.Hasta]++
+	}
+
+	niveles := map[string]int{}
+	recorrer := func(raiz string) {
+		niveles[raiz] = 0
+		cola := []string{raiz}
+		for len(cola) > 0 {
+			actual := cola[0]
+			cola = cola[1:]
+			for _, h := range hijos[actual] {
+				if _, visto := niveles[h]; !visto {
+					niveles[h] = niveles[actual] + 1
+					cola = append(cola, h)
+				}
+			}
+		}
+	}
+	for _, n := range grafo.Nodos {
+		if _, visto := niveles[n.ID]; !visto && entrantes[n.ID] == 0 {
+			recorrer(n.ID)
+		}
+	}
+	for _, n := range grafo.Nodos {
+		if _, visto := niveles[n.ID]; !visto {
+			recorrer(n.ID)
+		}
+	}
+	return niveles
+}
+
+// aSVG usa Graphviz si está instalado, que distribuye mejor los nodos; sin
+// él dibuja el grafo en Go
+func (grafo grafoReporte) aSVG() string {
+	svg, err := ejecutarGraphviz(grafo.aDot(), "svg")
+	if err != nil {
+		fmt.Printf("🔧 DEBUG: Graphviz no disponible (%v), usando SVG propio\n", err)
+		return grafo.aDibujo().aSVG()
+	}
+	return string(svg)
+}
+
+// aDibujo distribuye el grafo sin depender de Graphviz, con los nodos en
+// columnas por nivel y flechas entre ellos
+func (grafo grafoReporte) aDibujo() dibujo {
+	const (
+		altoFila   = 18
+		separacion = 60
+		margen     = 20
+		titulo     = 30
+	)
+
+	type caja struct {
+		x, y, ancho, alto int
+	}
+
+	// Tamaño de cada nodo según su contenido
+	cajas := make(map[string]*caja, len(grafo.Nodos))
+	lineas := func(n nodoReporte) []string {
+		salida := []string{}
+		for _, fila := range n.Filas {
+			for i, parte := range strings.Split(fila[1], "\n") {
+				if i == 0 {
+					salida = append(salida, fila[0]+": "+parte)
+				} else {
+					salida = append(salida, "    "+parte)
+				}
+			}
+		}
+		return salida
+	}
+	for _, n := range grafo.Nodos {
+		ancho := len([]rune(n.Titulo))
+		filas := lineas(n)
+		for _, l := range filas {
+			if w := len([]rune(l)); w > ancho {
+				ancho = w
+			}
+		}
+		cajas[n.ID] = &caja{ancho: ancho*7 + 20, alto: (len(filas)+1)*altoFila + 8}
+	}
+
+	// Columnas por nivel
+	niveles := nivelesGrafo(grafo)
+	maxNivel := 0
+	for _, nivel := range niveles {
+		if nivel > maxNivel {
+			maxNivel = nivel
+		}
+	}
+	anchoColumna := make([]int, maxNivel+1)
+	altoColumna := make([]int, maxNivel+1)
+	for _, n := range grafo.Nodos {
+		c, nivel := cajas[n.ID], niveles[n.ID]
+		if c.ancho > anchoColumna[nivel] {
+			anchoColumna[nivel] = c.ancho
+		}
+		c.y = margen + titulo + altoColumna[nivel]
+		altoColumna[nivel] += c.alto + separacion/2
+	}
+	xColumna := make([]int, maxNivel+1)
+	x := margen
+	for i := range anchoColumna {
+		xColumna[i] = x
+		x += anchoColumna[i] + separacion
+	}
+	altoTotal := 0
+	for _, a := range altoColumna {
+		if a > altoTotal {
+			altoTotal = a
+		}
+	}
+	for _, n := range grafo.Nodos {
+		cajas[n.ID].x = xColumna[niveles[n.ID]]
+	}
+
+	d := dibujo{Ancho: x - separacion + margen, Alto: altoTotal + titulo + 2*margen}
+	d.texto(figTexto{x: margen, y: margen + 14, contenido: grafo.Titulo, tamaño: 15, negrita: true})
+
+	for _, a := range grafo.Aristas {
+		desde, hasta := cajas[a.Desde], cajas[a.Hasta]
+		if desde == nil || hasta == nil {
+			continue
+		}
+		x1, y1 := desde.x+desde.ancho, desde.y+altoFila/2+4
+		x2, y2 := hasta.x, hasta.y+altoFila/2+4
+		if hasta.x <= desde.x {
+			// Enlace hacia atrás o en la misma columna: sale por abajo
+			x1, y1 = desde.x+desde.ancho/2, desde.y+desde.alto
+			x2, y2 = hasta.x+hasta.ancho/2, hasta.y
+			if hasta.y <= desde.y {
+				y2 = hasta.y + hasta.alto
+			}
+		}
+		d.linea(x1, y1, x2, y2, "#333", true)
+		if a.Etiqueta != "" {
+			d.texto(figTexto{x: (x1 + x2) / 2, y: (y1+y2)/2 - 3, contenido: a.Etiqueta, color: "#555", tamaño: 10, mono: true})
+		}
+	}
+
+	for _, n := range grafo.Nodos {
+		c := cajas[n.ID]
+		d.rectangulo(c.x, c.y, c.ancho, c.alto, "white", "#333")
+		d.rectangulo(c.x, c.y, c.ancho, altoFila+4, n.Color, "#333")
+		d.texto(figTexto{x: c.x + 8, y: c.y + altoFila - 2, contenido: n.Titulo, color: "white", negrita: true, mono: true})
+		for i, l := range lineas(n) {
+			d.texto(figTexto{x: c.x + 8, y: c.y + (i+2)*altoFila, contenido: l, mono: true})
+		}
+	}
+	return d
+}
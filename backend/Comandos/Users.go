@@ -1,15 +1,10 @@
 package Comandos
 
 import (
-	"bytes"
-	"encoding/binary"
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
-	"unsafe"
 
-	"godisk-backend/Structs"
 	"godisk-backend/Utils"
 )
 
@@ -130,50 +125,27 @@ func mkusr(usuario, password, grupo string) string {
 
 	sesion := ObtenerSesionActiva()
 
-	// Obtener la partición montada de la sesión activa
-	var pathDisco string
-	particion := GetMount("MKUSR", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("MKUSR", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo para lectura primero
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición de la sesión activa
+	v, err := abrirVolumen("MKUSR", sesion.Id)
 	if err != nil {
-		return Utils.Error("MKUSR", "No se encontró el disco: "+err.Error())
-	}
-	defer file.Close()
-
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytesUsers(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		return Utils.Error("MKUSR", "Error al leer superbloque: "+err_.Error())
+		return Utils.Error("MKUSR", err.Error())
 	}
+	defer v.Cerrar()
 
-	fmt.Printf("🔧 DEBUG: SuperBloque leído correctamente\n")
-
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytesUsers(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		return Utils.Error("MKUSR", "Error al leer inodo users.txt: "+err_.Error())
+	if err := contextoSesion(v).crearUsuario(usuario, password, grupo); err != nil {
+		return Utils.Error("MKUSR", err.Error())
 	}
 
-	fmt.Printf("🔧 DEBUG: Inodo users.txt leído - Tamaño: %d\n", inodo.I_size)
+	fmt.Printf("✅ MKUSR: Usuario '%s' creado correctamente\n", usuario)
+	return Utils.Mensaje("MKUSR", "Usuario "+usuario+", creado correctamente!")
+}
 
-	// ✅ USAR EXACTAMENTE LA MISMA FUNCIÓN QUE MKGRP (de Login.go)
-	contenidoActual := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoActual == "" {
-		return Utils.Error("MKUSR", "No se pudo leer el archivo users.txt")
+// crearUsuario agrega el usuario a users.txt dentro de un grupo existente
+func (c *contextoFS) crearUsuario(usuario, password, grupo string) error {
+	// Leer contenido actual de users.txt
+	nUsers, inodo, contenidoActual, err := leerUsuarios(c.v)
+	if err != nil || contenidoActual == "" {
+		return fmt.Errorf("no se pudo leer el archivo users.txt")
 	}
 
 	fmt.Printf("🔧 DEBUG: Contenido actual users.txt:\n%s\n", contenidoActual)
@@ -199,7 +171,7 @@ func mkusr(usuario, password, grupo string) string {
 	}
 
 	if !grupoExiste {
-		return Utils.Error("MKUSR", "No se encontró el grupo \""+grupo+"\".")
+		return fmt.Errorf("no se encontró el grupo \"%s\"", grupo)
 	}
 
 	// Verificar si el usuario ya existe y contar usuarios
@@ -217,7 +189,7 @@ func mkusr(usuario, password, grupo string) string {
 				nombreUsuario := campos[3]
 				if nombreUsuario == usuario {
 					if linea[0] != '0' { // Si no está eliminado
-						return Utils.Error("MKUSR", "EL nombre "+usuario+", ya está en uso.")
+						return fmt.Errorf("el nombre %s ya está en uso", usuario)
 					}
 				}
 			}
@@ -233,13 +205,17 @@ func mkusr(usuario, password, grupo string) string {
 
 	fmt.Printf("🔧 DEBUG: Nuevo contenido users.txt:\n%s\n", nuevoContenido)
 
+	// Registrar la operación en el journal antes de modificar users.txt
+	if err := c.registrar("mkusr", "/users.txt", usuario+","+password+","+grupo); err != nil {
+		return err
+	}
+
 	// ✅ USAR EXACTAMENTE LA MISMA FUNCIÓN QUE MKGRP (de Groups.go)
-	if err := escribirContenidoUsersLocal(pathDisco, *particion, super, inodo, nuevoContenido); err != nil {
-		return Utils.Error("MKUSR", "Error al escribir en users.txt: "+err.Error())
+	if err := c.v.EscribirArchivo(nUsers, &inodo, []byte(nuevoContenido)); err != nil {
+		return fmt.Errorf("error al escribir en users.txt: %v", err)
 	}
 
-	fmt.Printf("✅ MKUSR: Usuario '%s' creado correctamente\n", usuario)
-	return Utils.Mensaje("MKUSR", "Usuario "+usuario+", creado correctamente!")
+	return nil
 }
 
 // rmusr elimina un usuario - USANDO EXACTAMENTE LA MISMA ARQUITECTURA QUE RMGRP
@@ -248,46 +224,27 @@ func rmusr(usuario string) string {
 
 	sesion := ObtenerSesionActiva()
 
-	// Obtener la partición montada de la sesión activa
-	var pathDisco string
-	particion := GetMount("RMUSR", sesion.Id, &pathDisco)
-	if particion == nil {
-		return Utils.Error("RMUSR", "No se encontró la partición montada con el ID: "+sesion.Id)
-	}
-
-	fmt.Printf("🔧 DEBUG: Partición encontrada en: %s\n", pathDisco)
-
-	// Abrir archivo para lectura primero
-	file, err := os.Open(strings.ReplaceAll(pathDisco, "\"", ""))
+	// Abrir el sistema de archivos de la partición de la sesión activa
+	v, err := abrirVolumen("RMUSR", sesion.Id)
 	if err != nil {
-		return Utils.Error("RMUSR", "No se encontró el disco: "+err.Error())
-	}
-	defer file.Close()
-
-	// Leer SuperBloque
-	super := Structs.NewSuperBloque()
-	file.Seek(particion.Part_start, 0)
-	data := leerBytesUsers(file, int(unsafe.Sizeof(Structs.SuperBloque{})))
-	buffer := bytes.NewBuffer(data)
-	err_ := binary.Read(buffer, binary.BigEndian, &super)
-	if err_ != nil {
-		return Utils.Error("RMUSR", "Error al leer superbloque: "+err_.Error())
+		return Utils.Error("RMUSR", err.Error())
 	}
+	defer v.Cerrar()
 
-	// Leer inodo del archivo users.txt (inodo 1)
-	inodo := Structs.NewInodos()
-	file.Seek(super.S_inode_start+int64(unsafe.Sizeof(Structs.Inodos{})), 0)
-	data = leerBytesUsers(file, int(unsafe.Sizeof(Structs.Inodos{})))
-	buffer = bytes.NewBuffer(data)
-	err_ = binary.Read(buffer, binary.BigEndian, &inodo)
-	if err_ != nil {
-		return Utils.Error("RMUSR", "Error al leer inodo users.txt: "+err_.Error())
+	if err := contextoSesion(v).eliminarUsuario(usuario); err != nil {
+		return Utils.Error("RMUSR", err.Error())
 	}
 
-	// ✅ USAR EXACTAMENTE LA MISMA FUNCIÓN QUE RMGRP (de Login.go)
-	contenidoActual := leerContenidoUsersArchivo(file, super, inodo)
-	if contenidoActual == "" {
-		return Utils.Error("RMUSR", "No se pudo leer el archivo users.txt")
+	fmt.Printf("✅ RMUSR: Usuario '%s' eliminado correctamente\n", usuario)
+	return Utils.Mensaje("RMUSR", "Usuario "+usuario+", eliminado correctamente!")
+}
+
+// eliminarUsuario marca el usuario como eliminado (ID 0) en users.txt
+func (c *contextoFS) eliminarUsuario(usuario string) error {
+	// Leer contenido actual de users.txt
+	nUsers, inodo, contenidoActual, err := leerUsuarios(c.v)
+	if err != nil || contenidoActual == "" {
+		return fmt.Errorf("no se pudo leer el archivo users.txt")
 	}
 
 	fmt.Printf("🔧 DEBUG: Contenido actual users.txt:\n%s\n", contenidoActual)
@@ -319,7 +276,7 @@ func rmusr(usuario string) string {
 	}
 
 	if !usuarioEncontrado {
-		return Utils.Error("RMUSR", "No se encontró el usuario  \""+usuario+"\".")
+		return fmt.Errorf("no se encontró el usuario \"%s\"", usuario)
 	}
 
 	// ✅ PRESERVAR CONTENIDO - Reconstruir contenido manteniendo todas las líneas
@@ -327,31 +284,15 @@ func rmusr(usuario string) string {
 
 	fmt.Printf("🔧 DEBUG: Nuevo contenido users.txt:\n%s\n", nuevoContenido)
 
-	// ✅ USAR EXACTAMENTE LA MISMA FUNCIÓN QUE RMGRP (de Groups.go)
-	if err := escribirContenidoUsersLocal(pathDisco, *particion, super, inodo, nuevoContenido); err != nil {
-		return Utils.Error("RMUSR", "Error al escribir en users.txt: "+err.Error())
+	// Registrar la operación en el journal antes de modificar users.txt
+	if err := c.registrar("rmusr", "/users.txt", usuario); err != nil {
+		return err
 	}
 
-	fmt.Printf("✅ RMUSR: Usuario '%s' eliminado correctamente\n", usuario)
-	return Utils.Mensaje("RMUSR", "Usuario "+usuario+", eliminado correctamente!")
-}
-
-// leerBytesUsers función auxiliar para leer bytes del archivo
-func leerBytesUsers(file *os.File, size int) []byte {
-	bytes := make([]byte, size)
-	file.Read(bytes)
-	return bytes
-}
-
-// escribirContenidoUsersLocal delega en la función compartida escribirContenidoArchivo
-func escribirContenidoUsersLocal(pathDisco string, particion Structs.Particion, super Structs.SuperBloque, inodo Structs.Inodos, nuevoContenido string) error {
-	return escribirContenidoArchivo(pathDisco, particion, super, inodo, nuevoContenido)
-}
-
-// helper min
-func min(a, b int) int {
-	if a < b {
-		return a
+	// ✅ USAR EXACTAMENTE LA MISMA FUNCIÓN QUE RMGRP (de Groups.go)
+	if err := c.v.EscribirArchivo(nUsers, &inodo, []byte(nuevoContenido)); err != nil {
+		return fmt.Errorf("error al escribir en users.txt: %v", err)
 	}
-	return b
+
+	return nil
 }
@@ -0,0 +1,272 @@
+package FS
+
+import (
+	"fmt"
+
+	"godisk-backend/Structs"
+)
+
+// Distribución de I_block: 13 apuntadores directos y los 3 últimos para
+// indirección simple, doble y triple
+const (
+	BloquesDirectos   = 13
+	IndiceSimple      = 13
+	IndiceDoble       = 14
+	IndiceTriple      = 15
+	PunterosPorBloque = 16
+)
+
+// MaxBloquesInodo es la cantidad máxima de bloques de datos por inodo
+const MaxBloquesInodo = BloquesDirectos + PunterosPorBloque + PunterosPorBloque*PunterosPorBloque +
+	PunterosPorBloque*PunterosPorBloque*PunterosPorBloque
+
+// capacidadNivel retorna cuántos bloques de datos cubre un apuntador de un nivel dado
+func capacidadNivel(nivel int) int {
+	capacidad := 1
+	for i := 0; i < nivel; i++ {
+		capacidad *= PunterosPorBloque
+	}
+	return capacidad
+}
+
+// BloquesTotales retorna cuántos bloques ocupa un inodo con datos bloques de
+// datos, contando los bloques de apuntadores que AjustarBloques crea para ellos
+func BloquesTotales(datos int) int {
+	total := datos
+	resto := datos - BloquesDirectos
+	for nivel := 1; nivel <= 3 && resto > 0; nivel++ {
+		tomar := min(resto, capacidadNivel(nivel))
+		for j := 1; j <= nivel; j++ {
+			divisor := capacidadNivel(j)
+			total += (tomar + divisor - 1) / divisor
+		}
+		resto -= tomar
+	}
+	return total
+}
+
+// BloquesDeInodo retorna, en orden lógico, los bloques de datos de un inodo
+// recorriendo los apuntadores directos y los indirectos
+func (v *Volumen) BloquesDeInodo(inodo Structs.Inodos) ([]int64, error) {
+	var bloques []int64
+
+	for i := 0; i < BloquesDirectos; i++ {
+		if inodo.I_block[i] != -1 {
+			bloques = append(bloques, inodo.I_block[i])
+		}
+	}
+
+	for nivel, indice := range []int{IndiceSimple, IndiceDoble, IndiceTriple} {
+		if inodo.I_block[indice] == -1 {
+			continue
+		}
+		if err := v.recorrerApuntadores(inodo.I_block[indice], nivel+1, &bloques); err != nil {
+			return nil, err
+		}
+	}
+
+	return bloques, nil
+}
+
+// BloquesApuntadoresDeInodo retorna los bloques de apuntadores de un inodo
+func (v *Volumen) BloquesApuntadoresDeInodo(inodo Structs.Inodos) ([]int64, error) {
+	var apuntadores []int64
+
+	var recorrer func(n int64, nivel int) error
+	recorrer = func(n int64, nivel int) error {
+		apuntadores = append(apuntadores, n)
+		if nivel == 1 {
+			return nil
+		}
+		var ap Structs.BloquesApuntadores
+		if err := v.ReadBlock(n, &ap); err != nil {
+			return err
+		}
+		for _, p := range ap.B_pointers {
+			if p != -1 {
+				if err := recorrer(int64(p), nivel-1); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for nivel, indice := range []int{IndiceSimple, IndiceDoble, IndiceTriple} {
+		if inodo.I_block[indice] == -1 {
+			continue
+		}
+		if err := recorrer(inodo.I_block[indice], nivel+1); err != nil {
+			return nil, err
+		}
+	}
+	return apuntadores, nil
+}
+
+// recorrerApuntadores agrega a bloques los bloques de datos alcanzables desde
+// un bloque de apuntadores del nivel indicado
+func (v *Volumen) recorrerApuntadores(n int64, nivel int, bloques *[]int64) error {
+	var ap Structs.BloquesApuntadores
+	if err := v.ReadBlock(n, &ap); err != nil {
+		return err
+	}
+
+	for _, p := range ap.B_pointers {
+		if p == -1 {
+			continue
+		}
+		if nivel == 1 {
+			*bloques = append(*bloques, int64(p))
+		} else if err := v.recorrerApuntadores(int64(p), nivel-1, bloques); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AjustarBloques deja al inodo con exactamente cantidad bloques de datos.
+// Conserva los bloques existentes, asigna los que falten, libera los sobrantes
+// y crea o libera los bloques de apuntadores necesarios. Retorna la lista
+// ordenada de bloques de datos; el inodo debe persistirse después.
+func (v *Volumen) AjustarBloques(inodo *Structs.Inodos, cantidad int) ([]int64, error) {
+	if cantidad > MaxBloquesInodo {
+		return nil, fmt.Errorf("se requieren %d bloques y un inodo admite como máximo %d", cantidad, MaxBloquesInodo)
+	}
+
+	actuales, err := v.BloquesDeInodo(*inodo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verificar el espacio antes de asignar, para no dejar bloques marcados
+	// en el bitmap que ningún inodo referencia si se acaban a la mitad
+	if cantidad > len(actuales) {
+		necesarios := int64(BloquesTotales(cantidad) - BloquesTotales(len(actuales)))
+		if necesarios > v.Super.S_free_blocks_count {
+			return nil, fmt.Errorf("no hay bloques libres suficientes (se necesitan %d, hay %d)", necesarios, v.Super.S_free_blocks_count)
+		}
+	}
+
+	// Liberar bloques de datos sobrantes
+	for len(actuales) > cantidad {
+		if err := v.LiberarBloque(actuales[len(actuales)-1]); err != nil {
+			return nil, err
+		}
+		actuales = actuales[:len(actuales)-1]
+	}
+
+	// Asignar bloques de datos faltantes
+	for len(actuales) < cantidad {
+		nBloque, err := v.AsignarBloque()
+		if err != nil {
+			return nil, err
+		}
+		actuales = append(actuales, nBloque)
+	}
+
+	// Apuntadores directos
+	for i := 0; i < BloquesDirectos; i++ {
+		if i < len(actuales) {
+			inodo.I_block[i] = actuales[i]
+		} else {
+			inodo.I_block[i] = -1
+		}
+	}
+
+	// Apuntadores indirectos
+	var resto []int64
+	if len(actuales) > BloquesDirectos {
+		resto = actuales[BloquesDirectos:]
+	}
+	for nivel, indice := range []int{IndiceSimple, IndiceDoble, IndiceTriple} {
+		tomar := capacidadNivel(nivel + 1)
+		if tomar > len(resto) {
+			tomar = len(resto)
+		}
+		ptr, err := v.construirApuntadores(inodo.I_block[indice], nivel+1, resto[:tomar])
+		if err != nil {
+			return nil, err
+		}
+		inodo.I_block[indice] = ptr
+		resto = resto[tomar:]
+	}
+
+	return actuales, nil
+}
+
+// construirApuntadores actualiza el árbol de apuntadores de un nivel para que
+// referencie exactamente los bloques de datos dados. Si no quedan datos, libera
+// los bloques de apuntadores del árbol (los de datos se liberan aparte).
+func (v *Volumen) construirApuntadores(ptr int64, nivel int, datos []int64) (int64, error) {
+	if len(datos) == 0 {
+		if ptr != -1 {
+			if err := v.liberarApuntadores(ptr, nivel); err != nil {
+				return -1, err
+			}
+		}
+		return -1, nil
+	}
+
+	ap := Structs.NewBloquesApuntadores()
+	if ptr == -1 {
+		nBloque, err := v.AsignarBloque()
+		if err != nil {
+			return -1, err
+		}
+		ptr = nBloque
+	} else if err := v.ReadBlock(ptr, &ap); err != nil {
+		return -1, err
+	}
+
+	porHijo := capacidadNivel(nivel - 1)
+	for i := 0; i < PunterosPorBloque; i++ {
+		inicio := i * porHijo
+		if nivel == 1 {
+			if inicio < len(datos) {
+				ap.B_pointers[i] = int32(datos[inicio])
+			} else {
+				ap.B_pointers[i] = -1
+			}
+			continue
+		}
+
+		var parte []int64
+		if inicio < len(datos) {
+			fin := inicio + porHijo
+			if fin > len(datos) {
+				fin = len(datos)
+			}
+			parte = datos[inicio:fin]
+		}
+		hijo, err := v.construirApuntadores(int64(ap.B_pointers[i]), nivel-1, parte)
+		if err != nil {
+			return -1, err
+		}
+		ap.B_pointers[i] = int32(hijo)
+	}
+
+	if err := v.WriteBlock(ptr, ap); err != nil {
+		return -1, err
+	}
+	return ptr, nil
+}
+
+// liberarApuntadores libera un bloque de apuntadores y sus descendientes de
+// apuntadores, sin tocar los bloques de datos
+func (v *Volumen) liberarApuntadores(ptr int64, nivel int) error {
+	if nivel > 1 {
+		var ap Structs.BloquesApuntadores
+		if err := v.ReadBlock(ptr, &ap); err != nil {
+			return err
+		}
+		for _, p := range ap.B_pointers {
+			if p == -1 {
+				continue
+			}
+			if err := v.liberarApuntadores(int64(p), nivel-1); err != nil {
+				return err
+			}
+		}
+	}
+	return v.LiberarBloque(ptr)
+}
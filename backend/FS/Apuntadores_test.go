@@ -0,0 +1,137 @@
+package FS
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"godisk-backend/Structs"
+)
+
+// volumenPrueba crea en un archivo temporal un volumen con bloques bloques
+// libres y un inodo, sin estructura de carpetas
+func volumenPrueba(t *testing.T, bloques int64) *Volumen {
+	t.Helper()
+
+	super := Structs.NewSuperBloque()
+	super.S_filesystem_type = 2
+	super.S_inodes_count, super.S_free_inodes_count = 1, 1
+	super.S_blocks_count, super.S_free_blocks_count = bloques, bloques
+	super.S_bm_inode_start = Structs.TamSuperBloque
+	super.S_bm_block_start = super.S_bm_inode_start + super.S_inodes_count
+	super.S_inode_start = super.S_bm_block_start + super.S_blocks_count
+	super.S_block_start = super.S_inode_start + super.S_inodes_count*super.S_inode_size
+	tamaño := super.S_block_start + bloques*super.S_block_size
+
+	path := filepath.Join(t.TempDir(), "disco.mia")
+	if err := os.WriteFile(path, make([]byte, tamaño), 0644); err != nil {
+		t.Fatal(err)
+	}
+	particion := Structs.NewParticion()
+	particion.Part_start, particion.Part_size = 0, tamaño
+
+	v, err := Crear(path, particion, super)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { v.Cerrar() })
+
+	if err := v.EscribirBitmapInodos(bytes.Repeat([]byte{BitmapLibre}, int(super.S_inodes_count))); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.EscribirBitmapBloques(bytes.Repeat([]byte{BitmapLibre}, int(bloques))); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestAjustarBloques(t *testing.T) {
+	const sinIndirectos = BloquesDirectos
+	const conSimple = BloquesDirectos + PunterosPorBloque
+
+	casos := []struct {
+		nombre     string
+		cantidades []int // ajustes sucesivos sobre el mismo inodo
+	}{
+		{"solo directos", []int{1, sinIndirectos}},
+		{"cruza al indirecto simple", []int{sinIndirectos, sinIndirectos + 1}},
+		{"llena el indirecto simple", []int{conSimple}},
+		{"cruza al indirecto doble", []int{conSimple, conSimple + 1}},
+		{"crece de golpe al doble", []int{conSimple + 20}},
+		{"reduce del doble al simple", []int{conSimple + 20, conSimple}},
+		{"reduce del doble a directos", []int{conSimple + 1, sinIndirectos}},
+		{"reduce del simple a directos", []int{sinIndirectos + 1, sinIndirectos}},
+		{"libera todo", []int{conSimple + 5, 0}},
+		{"crece después de reducir", []int{conSimple + 3, 2, sinIndirectos + 4}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			v := volumenPrueba(t, 64)
+			inodo := Structs.NewInodos()
+
+			for _, cantidad := range c.cantidades {
+				lista, err := v.AjustarBloques(&inodo, cantidad)
+				if err != nil {
+					t.Fatalf("AjustarBloques(%d): %v", cantidad, err)
+				}
+				if len(lista) != cantidad {
+					t.Fatalf("AjustarBloques(%d) retornó %d bloques", cantidad, len(lista))
+				}
+
+				recorridos, err := v.BloquesDeInodo(inodo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(recorridos) != cantidad {
+					t.Fatalf("BloquesDeInodo retornó %d bloques, se esperaban %d", len(recorridos), cantidad)
+				}
+				for i := range lista {
+					if recorridos[i] != lista[i] {
+						t.Fatalf("bloque %d: el inodo apunta a %d y AjustarBloques retornó %d", i, recorridos[i], lista[i])
+					}
+				}
+
+				if got := (inodo.I_block[IndiceSimple] != -1); got != (cantidad > sinIndirectos) {
+					t.Errorf("con %d bloques el indirecto simple es %d", cantidad, inodo.I_block[IndiceSimple])
+				}
+				if got := (inodo.I_block[IndiceDoble] != -1); got != (cantidad > conSimple) {
+					t.Errorf("con %d bloques el indirecto doble es %d", cantidad, inodo.I_block[IndiceDoble])
+				}
+
+				// Datos y apuntadores deben ser exactamente los bloques ocupados
+				ocupados := int64(BloquesTotales(cantidad))
+				if usados := v.Super.S_blocks_count - v.Super.S_free_blocks_count; usados != ocupados {
+					t.Errorf("con %d bloques el superbloque tiene %d ocupados, se esperaban %d", cantidad, usados, ocupados)
+				}
+				bitmap, err := v.LeerBitmapBloques()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if n := int64(bytes.Count(bitmap, []byte{BitmapOcupado})); n != ocupados {
+					t.Errorf("con %d bloques el bitmap tiene %d ocupados, se esperaban %d", cantidad, n, ocupados)
+				}
+			}
+		})
+	}
+}
+
+func TestAjustarBloquesSinEspacio(t *testing.T) {
+	v := volumenPrueba(t, 20)
+	inodo := Structs.NewInodos()
+
+	if _, err := v.AjustarBloques(&inodo, 10); err != nil {
+		t.Fatal(err)
+	}
+	// 20 bloques de datos necesitan además el bloque del indirecto simple
+	if _, err := v.AjustarBloques(&inodo, 20); err == nil {
+		t.Fatal("AjustarBloques(20) en un volumen de 20 bloques debió fallar")
+	}
+	if v.Super.S_free_blocks_count != 10 {
+		t.Errorf("el intento fallido dejó %d bloques libres, se esperaban 10", v.Super.S_free_blocks_count)
+	}
+	if _, err := v.AjustarBloques(&inodo, MaxBloquesInodo+1); err == nil {
+		t.Errorf("AjustarBloques(%d) debió superar el máximo del inodo", MaxBloquesInodo+1)
+	}
+}
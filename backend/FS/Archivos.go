@@ -0,0 +1,116 @@
+package FS
+
+import (
+	"fmt"
+
+	"godisk-backend/Structs"
+)
+
+// TamContenidoBloque es la cantidad de bytes de datos en un bloque de archivo
+const TamContenidoBloque = len(Structs.BloquesArchivos{}.B_content)
+
+// LeerArchivo retorna el contenido completo de un inodo de archivo
+// recorriendo sus bloques directos e indirectos
+func (v *Volumen) LeerArchivo(inodo Structs.Inodos) (string, error) {
+	if inodo.I_type != TipoArchivo {
+		return "", fmt.Errorf("el inodo no es un archivo")
+	}
+
+	bloques, err := v.BloquesDeInodo(inodo)
+	if err != nil {
+		return "", err
+	}
+
+	contenido := make([]byte, 0, len(bloques)*TamContenidoBloque)
+	for _, nBloque := range bloques {
+		var bloque Structs.BloquesArchivos
+		if err := v.ReadBlock(nBloque, &bloque); err != nil {
+			return "", err
+		}
+		contenido = append(contenido, bloque.B_content[:]...)
+	}
+
+	if inodo.I_size >= 0 && inodo.I_size < int64(len(contenido)) {
+		contenido = contenido[:inodo.I_size]
+	}
+	return string(contenido), nil
+}
+
+// EscribirArchivo reemplaza el contenido del inodo n: ajusta su lista de
+// bloques (asignando o liberando), escribe los datos y actualiza I_size e
+// I_mtime. El inodo recibido queda actualizado y persistido.
+func (v *Volumen) EscribirArchivo(n int64, inodo *Structs.Inodos, contenido []byte) error {
+	cantidad := (len(contenido) + TamContenidoBloque - 1) / TamContenidoBloque
+	if cantidad > MaxBloquesInodo {
+		return fmt.Errorf("contenido demasiado grande para el archivo")
+	}
+
+	bloques, err := v.AjustarBloques(inodo, cantidad)
+	if err != nil {
+		return err
+	}
+
+	for i, nBloque := range bloques {
+		var bloque Structs.BloquesArchivos
+		inicio := i * TamContenidoBloque
+		fin := inicio + TamContenidoBloque
+		if fin > len(contenido) {
+			fin = len(contenido)
+		}
+		copy(bloque.B_content[:], contenido[inicio:fin])
+		if err := v.WriteBlock(nBloque, bloque); err != nil {
+			return err
+		}
+	}
+
+	inodo.I_size = int64(len(contenido))
+	inodo.I_mtime = FechaActual()
+	return v.WriteInode(n, *inodo)
+}
+
+// CrearArchivo crea el archivo nombre dentro de nPadre con el contenido dado
+// y retorna el número de inodo asignado
+func (v *Volumen) CrearArchivo(nPadre int64, nombre string, uid, gid int64, contenido []byte) (int64, error) {
+	nInodo, err := v.AsignarInodo()
+	if err != nil {
+		return -1, err
+	}
+
+	inodo := Structs.NewInodos()
+	inodo.I_uid = uid
+	inodo.I_gid = gid
+	inodo.I_type = TipoArchivo
+	inodo.I_perm = 664
+	fecha := FechaActual()
+	inodo.I_atime, inodo.I_ctime, inodo.I_mtime = fecha, fecha, fecha
+
+	if err := v.EscribirArchivo(nInodo, &inodo, contenido); err != nil {
+		v.AjustarBloques(&inodo, 0)
+		v.LiberarInodo(nInodo)
+		return -1, err
+	}
+
+	if err := v.AgregarEntrada(nPadre, nombre, nInodo); err != nil {
+		v.AjustarBloques(&inodo, 0)
+		v.LiberarInodo(nInodo)
+		return -1, err
+	}
+	return nInodo, nil
+}
+
+// EliminarInodo libera los bloques de datos y de apuntadores del inodo n y
+// después el inodo. No toca la entrada que lo referencia.
+func (v *Volumen) EliminarInodo(n int64) error {
+	inodo, err := v.ReadInode(n)
+	if err != nil {
+		return err
+	}
+	if _, err := v.AjustarBloques(&inodo, 0); err != nil {
+		return err
+	}
+	inodo.I_size = 0
+	if err := v.WriteInode(n, inodo); err != nil {
+		return err
+	}
+	return v.LiberarInodo(n)
+}
@@ -0,0 +1,200 @@
+package FS
+
+import (
+	"fmt"
+)
+
+// Valores usados en los bitmaps (mkfs los inicializa en ASCII)
+const (
+	BitmapLibre   byte = '0'
+	BitmapOcupado byte = '1'
+)
+
+// LeerBitmapInodos lee el bitmap de inodos completo
+func (v *Volumen) LeerBitmapInodos() ([]byte, error) {
+	return v.leerBitmap(v.Super.S_bm_inode_start, v.Super.S_inodes_count)
+}
+
+// LeerBitmapBloques lee el bitmap de bloques completo
+func (v *Volumen) LeerBitmapBloques() ([]byte, error) {
+	return v.leerBitmap(v.Super.S_bm_block_start, v.Super.S_blocks_count)
+}
+
+// leerBitmap lee un bitmap completo de n entradas desde la posición inicio
+func (v *Volumen) leerBitmap(inicio, n int64) ([]byte, error) {
+	bitmap := make([]byte, n)
+	if _, err := v.Archivo.ReadAt(bitmap, inicio); err != nil {
+		return nil, fmt.Errorf("error al leer bitmap: %v", err)
+	}
+	return bitmap, nil
+}
+
+// buscarLibreEnBitmap busca una entrada libre usando la estrategia de ajuste
+// de la partición (F = primer ajuste, B = mejor ajuste, W = peor ajuste).
+// Para mejor y peor ajuste se elige el inicio del hueco libre más pequeño o
+// más grande respectivamente. Retorna -1 si no hay entradas libres.
+func buscarLibreEnBitmap(bitmap []byte, ajuste byte) int64 {
+	mejorInicio := int64(-1)
+	mejorTam := int64(0)
+
+	for i := int64(0); i < int64(len(bitmap)); {
+		if bitmap[i] == BitmapOcupado {
+			i++
+			continue
+		}
+
+		// Medir el hueco libre que empieza en i
+		inicio := i
+		for i < int64(len(bitmap)) && bitmap[i] != BitmapOcupado {
+			i++
+		}
+		tam := i - inicio
+
+		switch ajuste {
+		case 'B', 'b':
+			if mejorInicio == -1 || tam < mejorTam {
+				mejorInicio, mejorTam = inicio, tam
+			}
+		case 'W', 'w':
+			if mejorInicio == -1 || tam > mejorTam {
+				mejorInicio, mejorTam = inicio, tam
+			}
+		default: // First Fit
+			return inicio
+		}
+	}
+
+	return mejorInicio
+}
+
+// PrimerLibre retorna la primera entrada libre del bitmap o -1
+func PrimerLibre(bitmap []byte) int64 {
+	return buscarLibreEnBitmap(bitmap, 'F')
+}
+
+// AsignarBloque reserva un bloque libre en el bitmap de bloques, actualiza los
+// contadores del superbloque y lo persiste. Retorna el número de bloque.
+func (v *Volumen) AsignarBloque() (int64, error) {
+	bitmap, err := v.LeerBitmapBloques()
+	if err != nil {
+		return -1, err
+	}
+
+	n := buscarLibreEnBitmap(bitmap, v.Particion.Part_fit)
+	if n == -1 {
+		return -1, fmt.Errorf("no hay bloques libres en la partición")
+	}
+
+	if _, err := v.Archivo.WriteAt([]byte{BitmapOcupado}, v.Super.S_bm_block_start+n); err != nil {
+		return -1, err
+	}
+	bitmap[n] = BitmapOcupado
+
+	v.Super.S_free_blocks_count--
+	v.Super.S_first_blo = PrimerLibre(bitmap)
+	if err := v.WriteSuperblock(); err != nil {
+		return -1, err
+	}
+
+	fmt.Printf("🔧 DEBUG: Bloque %d asignado (libres: %d)\n", n, v.Super.S_free_blocks_count)
+	return n, nil
+}
+
+// AsignarInodo reserva un inodo libre en el bitmap de inodos, actualiza los
+// contadores del superbloque y lo persiste. Retorna el número de inodo.
+func (v *Volumen) AsignarInodo() (int64, error) {
+	bitmap, err := v.LeerBitmapInodos()
+	if err != nil {
+		return -1, err
+	}
+
+	n := buscarLibreEnBitmap(bitmap, v.Particion.Part_fit)
+	if n == -1 {
+		return -1, fmt.Errorf("no hay inodos libres en la partición")
+	}
+
+	if _, err := v.Archivo.WriteAt([]byte{BitmapOcupado}, v.Super.S_bm_inode_start+n); err != nil {
+		return -1, err
+	}
+	bitmap[n] = BitmapOcupado
+
+	v.Super.S_free_inodes_count--
+	v.Super.S_firts_ino = PrimerLibre(bitmap)
+	if err := v.WriteSuperblock(); err != nil {
+		return -1, err
+	}
+
+	fmt.Printf("🔧 DEBUG: Inodo %d asignado (libres: %d)\n", n, v.Super.S_free_inodes_count)
+	return n, nil
+}
+
+// LiberarBloque devuelve un bloque al bitmap y actualiza el superbloque
+func (v *Volumen) LiberarBloque(n int64) error {
+	if n < 0 || n >= v.Super.S_blocks_count {
+		return fmt.Errorf("bloque fuera de rango: %d", n)
+	}
+
+	estado := make([]byte, 1)
+	if _, err := v.Archivo.ReadAt(estado, v.Super.S_bm_block_start+n); err != nil {
+		return err
+	}
+	if estado[0] != BitmapOcupado {
+		return nil // Ya estaba libre, los contadores no cambian
+	}
+
+	if _, err := v.Archivo.WriteAt([]byte{BitmapLibre}, v.Super.S_bm_block_start+n); err != nil {
+		return err
+	}
+
+	v.Super.S_free_blocks_count++
+	if v.Super.S_first_blo == -1 || n < v.Super.S_first_blo {
+		v.Super.S_first_blo = n
+	}
+	return v.WriteSuperblock()
+}
+
+// LiberarInodo devuelve un inodo al bitmap y actualiza el superbloque
+func (v *Volumen) LiberarInodo(n int64) error {
+	if n < 0 || n >= v.Super.S_inodes_count {
+		return fmt.Errorf("inodo fuera de rango: %d", n)
+	}
+
+	estado := make([]byte, 1)
+	if _, err := v.Archivo.ReadAt(estado, v.Super.S_bm_inode_start+n); err != nil {
+		return err
+	}
+	if estado[0] != BitmapOcupado {
+		return nil
+	}
+
+	if _, err := v.Archivo.WriteAt([]byte{BitmapLibre}, v.Super.S_bm_inode_start+n); err != nil {
+		return err
+	}
+
+	v.Super.S_free_inodes_count++
+	if v.Super.S_firts_ino == -1 || n < v.Super.S_firts_ino {
+		v.Super.S_firts_ino = n
+	}
+	return v.WriteSuperblock()
+}
+
+// EscribirBitmapInodos reemplaza el bitmap de inodos completo
+func (v *Volumen) EscribirBitmapInodos(bitmap []byte) error {
+	return v.escribirBitmap(v.Super.S_bm_inode_start, v.Super.S_inodes_count, bitmap)
+}
+
+// EscribirBitmapBloques reemplaza el bitmap de bloques completo
+func (v *Volumen) EscribirBitmapBloques(bitmap []byte) error {
+	return v.escribirBitmap(v.Super.S_bm_block_start, v.Super.S_blocks_count, bitmap)
+}
+
+// escribirBitmap escribe un bitmap de n entradas en la posición inicio
+func (v *Volumen) escribirBitmap(inicio, n int64, bitmap []byte) error {
+	if int64(len(bitmap)) != n {
+		return fmt.Errorf("el bitmap debe tener %d entradas", n)
+	}
+	if _, err := v.Archivo.WriteAt(bitmap, inicio); err != nil {
+		return fmt.Errorf("error al escribir bitmap: %v", err)
+	}
+	return nil
+}
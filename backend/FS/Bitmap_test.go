@@ -0,0 +1,35 @@
+package FS
+
+import "testing"
+
+func TestBuscarLibreEnBitmap(t *testing.T) {
+	casos := []struct {
+		nombre string
+		bitmap string
+		ajuste byte
+		espera int64
+	}{
+		{"vacío", "", 'F', -1},
+		{"lleno primer ajuste", "1111", 'F', -1},
+		{"lleno mejor ajuste", "1111", 'B', -1},
+		{"lleno peor ajuste", "1111", 'W', -1},
+		{"todo libre", "0000", 'B', 0},
+		// Huecos: [1,4) de 3, [5,6) de 1 y [7,9) de 2
+		{"primer ajuste", "100010100", 'F', 1},
+		{"mejor ajuste", "100010100", 'B', 5},
+		{"peor ajuste", "100010100", 'W', 1},
+		{"minúsculas", "100010100", 'b', 5},
+		{"ajuste desconocido usa el primero", "100010100", 'X', 1},
+		{"hueco al final", "1110000", 'W', 3},
+		{"empate en mejor ajuste", "0010011", 'B', 0},
+		{"empate en peor ajuste", "0010011", 'W', 0},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			if got := buscarLibreEnBitmap([]byte(c.bitmap), c.ajuste); got != c.espera {
+				t.Errorf("buscarLibreEnBitmap(%q, %c) = %d, se esperaba %d", c.bitmap, c.ajuste, got, c.espera)
+			}
+		})
+	}
+}
@@ -0,0 +1,366 @@
+package FS
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/Structs"
+)
+
+// Tipos de inodo (I_type)
+const (
+	TipoCarpeta int64 = 0
+	TipoArchivo int64 = 1
+)
+
+// LongitudNombre es el largo máximo de B_name en una entrada de carpeta
+const LongitudNombre = len(Structs.Content{}.B_name)
+
+// Entrada describe una entrada ocupada de un directorio y su ubicación
+type Entrada struct {
+	Nombre string
+	Inodo  int64
+	Bloque int64 // bloque de carpetas que la contiene
+	Indice int   // posición dentro de B_content
+}
+
+// NombreEntrada convierte el B_name de una entrada a string
+func NombreEntrada(c Structs.Content) string {
+	nombre := ""
+	for _, b := range c.B_name {
+		if b == 0 {
+			break
+		}
+		nombre += string(b)
+	}
+	return nombre
+}
+
+// SepararRuta divide una ruta absoluta en sus componentes (sin vacíos)
+func SepararRuta(ruta string) []string {
+	var componentes []string
+	for _, c := range strings.Split(strings.TrimSpace(ruta), "/") {
+		if c != "" {
+			componentes = append(componentes, c)
+		}
+	}
+	return componentes
+}
+
+// Entradas retorna las entradas ocupadas de un directorio, incluidas "." y ".."
+func (v *Volumen) Entradas(dir Structs.Inodos) ([]Entrada, error) {
+	if dir.I_type != TipoCarpeta {
+		return nil, fmt.Errorf("el inodo no es una carpeta")
+	}
+
+	bloques, err := v.BloquesDeInodo(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entradas []Entrada
+	for _, nBloque := range bloques {
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(nBloque, &carpeta); err != nil {
+			return nil, err
+		}
+		for i, c := range carpeta.B_content {
+			if c.B_inodo == -1 {
+				continue
+			}
+			entradas = append(entradas, Entrada{
+				Nombre: NombreEntrada(c),
+				Inodo:  c.B_inodo,
+				Bloque: nBloque,
+				Indice: i,
+			})
+		}
+	}
+	return entradas, nil
+}
+
+// BuscarEnDirectorio retorna el inodo de la entrada con ese nombre o -1
+func (v *Volumen) BuscarEnDirectorio(dir Structs.Inodos, nombre string) (int64, error) {
+	entradas, err := v.Entradas(dir)
+	if err != nil {
+		return -1, err
+	}
+	for _, e := range entradas {
+		if e.Nombre == nombre {
+			return e.Inodo, nil
+		}
+	}
+	return -1, nil
+}
+
+// ResolvePath recorre la ruta desde el inodo raíz y retorna el número e
+// inodo de su último componente
+func (v *Volumen) ResolvePath(ruta string) (int64, Structs.Inodos, error) {
+	actual := int64(0)
+	inodo, err := v.ReadInode(actual)
+	if err != nil {
+		return -1, inodo, err
+	}
+
+	for _, componente := range SepararRuta(ruta) {
+		if inodo.I_type != TipoCarpeta {
+			return -1, inodo, fmt.Errorf("'%s' no es una carpeta en la ruta %s", componente, ruta)
+		}
+		siguiente, err := v.BuscarEnDirectorio(inodo, componente)
+		if err != nil {
+			return -1, inodo, err
+		}
+		if siguiente == -1 {
+			return -1, inodo, fmt.Errorf("no existe '%s' en la ruta %s", componente, ruta)
+		}
+		actual = siguiente
+		if inodo, err = v.ReadInode(actual); err != nil {
+			return -1, inodo, err
+		}
+	}
+
+	return actual, inodo, nil
+}
+
+// AgregarEntrada inserta nombre -> nHijo en el directorio nDir. Usa la primera
+// entrada libre y, si todos los bloques están llenos, agrega un bloque nuevo
+// (directo o indirecto) al directorio.
+func (v *Volumen) AgregarEntrada(nDir int64, nombre string, nHijo int64) error {
+	if len(nombre) == 0 || len(nombre) > LongitudNombre {
+		return fmt.Errorf("el nombre '%s' debe tener entre 1 y %d caracteres", nombre, LongitudNombre)
+	}
+
+	dir, err := v.ReadInode(nDir)
+	if err != nil {
+		return err
+	}
+	existente, err := v.BuscarEnDirectorio(dir, nombre)
+	if err != nil {
+		return err
+	}
+	if existente != -1 {
+		return fmt.Errorf("ya existe '%s' en el directorio", nombre)
+	}
+
+	bloques, err := v.BloquesDeInodo(dir)
+	if err != nil {
+		return err
+	}
+	for _, nBloque := range bloques {
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(nBloque, &carpeta); err != nil {
+			return err
+		}
+		for i := range carpeta.B_content {
+			if carpeta.B_content[i].B_inodo == -1 {
+				carpeta.B_content[i] = Structs.NewContent()
+				copy(carpeta.B_content[i].B_name[:], nombre)
+				carpeta.B_content[i].B_inodo = nHijo
+				if err := v.WriteBlock(nBloque, carpeta); err != nil {
+					return err
+				}
+				dir.I_mtime = FechaActual()
+				return v.WriteInode(nDir, dir)
+			}
+		}
+	}
+
+	// Todos los bloques están llenos: agregar uno nuevo al directorio
+	lista, err := v.AjustarBloques(&dir, len(bloques)+1)
+	if err != nil {
+		return err
+	}
+	carpeta := Structs.NewBloquesCarpetas()
+	copy(carpeta.B_content[0].B_name[:], nombre)
+	carpeta.B_content[0].B_inodo = nHijo
+	if err := v.WriteBlock(lista[len(lista)-1], carpeta); err != nil {
+		return err
+	}
+
+	dir.I_size = int64(len(lista)) * v.Super.S_block_size
+	dir.I_mtime = FechaActual()
+	return v.WriteInode(nDir, dir)
+}
+
+// BloquesParaEntrada retorna cuántos bloques asignaría AgregarEntrada en el
+// directorio: ninguno si tiene una entrada libre, o el bloque de carpetas
+// nuevo más los bloques de apuntadores que necesite
+func (v *Volumen) BloquesParaEntrada(dir Structs.Inodos) (int64, error) {
+	bloques, err := v.BloquesDeInodo(dir)
+	if err != nil {
+		return 0, err
+	}
+	for _, nBloque := range bloques {
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(nBloque, &carpeta); err != nil {
+			return 0, err
+		}
+		for _, c := range carpeta.B_content {
+			if c.B_inodo == -1 {
+				return 0, nil
+			}
+		}
+	}
+	return int64(BloquesTotales(len(bloques)+1) - BloquesTotales(len(bloques))), nil
+}
+
+// CrearDirectorio crea la carpeta nombre dentro de nPadre con sus entradas
+// "." y ".." y retorna el número de inodo asignado
+func (v *Volumen) CrearDirectorio(nPadre int64, nombre string, uid, gid int64) (int64, error) {
+	nInodo, err := v.AsignarInodo()
+	if err != nil {
+		return -1, err
+	}
+
+	inodo := Structs.NewInodos()
+	inodo.I_uid = uid
+	inodo.I_gid = gid
+	inodo.I_type = TipoCarpeta
+	inodo.I_perm = 664
+	fecha := FechaActual()
+	inodo.I_atime, inodo.I_ctime, inodo.I_mtime = fecha, fecha, fecha
+
+	lista, err := v.AjustarBloques(&inodo, 1)
+	if err != nil {
+		v.LiberarInodo(nInodo)
+		return -1, err
+	}
+	inodo.I_size = v.Super.S_block_size
+
+	carpeta := Structs.NewBloquesCarpetas()
+	copy(carpeta.B_content[0].B_name[:], ".")
+	carpeta.B_content[0].B_inodo = nInodo
+	copy(carpeta.B_content[1].B_name[:], "..")
+	carpeta.B_content[1].B_inodo = nPadre
+	if err := v.WriteBlock(lista[0], carpeta); err != nil {
+		return -1, err
+	}
+	if err := v.WriteInode(nInodo, inodo); err != nil {
+		return -1, err
+	}
+
+	if err := v.AgregarEntrada(nPadre, nombre, nInodo); err != nil {
+		v.AjustarBloques(&inodo, 0)
+		v.LiberarInodo(nInodo)
+		return -1, err
+	}
+	return nInodo, nil
+}
+
+// QuitarEntrada elimina la entrada nombre del directorio nDir. El bloque de
+// carpetas se conserva aunque quede vacío.
+func (v *Volumen) QuitarEntrada(nDir int64, nombre string) error {
+	if nombre == "." || nombre == ".." {
+		return fmt.Errorf("no se puede quitar la entrada '%s'", nombre)
+	}
+
+	dir, err := v.ReadInode(nDir)
+	if err != nil {
+		return err
+	}
+	entradas, err := v.Entradas(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entradas {
+		if e.Nombre != nombre {
+			continue
+		}
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(e.Bloque, &carpeta); err != nil {
+			return err
+		}
+		carpeta.B_content[e.Indice] = Structs.NewContent()
+		if err := v.WriteBlock(e.Bloque, carpeta); err != nil {
+			return err
+		}
+		dir.I_mtime = FechaActual()
+		return v.WriteInode(nDir, dir)
+	}
+	return fmt.Errorf("no existe '%s' en el directorio", nombre)
+}
+
+// ValidarNombre verifica que nombre pueda usarse como entrada de carpeta
+func ValidarNombre(nombre string) error {
+	if len(nombre) == 0 || len(nombre) > LongitudNombre {
+		return fmt.Errorf("el nombre '%s' debe tener entre 1 y %d caracteres", nombre, LongitudNombre)
+	}
+	if nombre == "." || nombre == ".." || strings.Contains(nombre, "/") {
+		return fmt.Errorf("nombre inválido: '%s'", nombre)
+	}
+	return nil
+}
+
+// RenombrarEntrada cambia el nombre de la entrada anterior del directorio nDir
+// por nuevo, sin tocar el inodo al que apunta
+func (v *Volumen) RenombrarEntrada(nDir int64, anterior, nuevo string) error {
+	if err := ValidarNombre(nuevo); err != nil {
+		return err
+	}
+	if anterior == "." || anterior == ".." {
+		return fmt.Errorf("no se puede renombrar '%s'", anterior)
+	}
+
+	dir, err := v.ReadInode(nDir)
+	if err != nil {
+		return err
+	}
+	entradas, err := v.Entradas(dir)
+	if err != nil {
+		return err
+	}
+
+	var encontrada *Entrada
+	for i, e := range entradas {
+		if e.Nombre == nuevo {
+			return fmt.Errorf("ya existe '%s' en el directorio", nuevo)
+		}
+		if e.Nombre == anterior {
+			encontrada = &entradas[i]
+		}
+	}
+	if encontrada == nil {
+		return fmt.Errorf("no existe '%s' en el directorio", anterior)
+	}
+
+	var carpeta Structs.BloquesCarpetas
+	if err := v.ReadBlock(encontrada.Bloque, &carpeta); err != nil {
+		return err
+	}
+	carpeta.B_content[encontrada.Indice].B_name = [LongitudNombre]byte{}
+	copy(carpeta.B_content[encontrada.Indice].B_name[:], nuevo)
+	if err := v.WriteBlock(encontrada.Bloque, carpeta); err != nil {
+		return err
+	}
+	dir.I_mtime = FechaActual()
+	return v.WriteInode(nDir, dir)
+}
+
+// ApuntarEntrada hace que la entrada nombre del directorio nDir apunte al
+// inodo nHijo. Se usa para actualizar ".." al mover una carpeta.
+func (v *Volumen) ApuntarEntrada(nDir int64, nombre string, nHijo int64) error {
+	dir, err := v.ReadInode(nDir)
+	if err != nil {
+		return err
+	}
+	entradas, err := v.Entradas(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entradas {
+		if e.Nombre != nombre {
+			continue
+		}
+		var carpeta Structs.BloquesCarpetas
+		if err := v.ReadBlock(e.Bloque, &carpeta); err != nil {
+			return err
+		}
+		carpeta.B_content[e.Indice].B_inodo = nHijo
+		if err := v.WriteBlock(e.Bloque, carpeta); err != nil {
+			return err
+		}
+		dir.I_mtime = FechaActual()
+		return v.WriteInode(nDir, dir)
+	}
+	return fmt.Errorf("no existe '%s' en el directorio", nombre)
+}
@@ -0,0 +1,185 @@
+package FS
+
+import (
+	"fmt"
+	"strings"
+
+	"godisk-backend/Structs"
+)
+
+// Tipos de sistema de archivos (S_filesystem_type)
+const (
+	SistemaEXT2 int64 = 2
+	SistemaEXT3 int64 = 3
+)
+
+// EsEXT3 indica si el volumen tiene área de journaling
+func (v *Volumen) EsEXT3() bool {
+	return v.Super.S_filesystem_type == SistemaEXT3
+}
+
+// CapacidadJournal retorna cuántas entradas caben en el área de journaling,
+// que va desde S_journal_start hasta el bitmap de inodos. MKFS reserva una
+// entrada por inodo y el journal no se sobrescribe: cuando se llena, los
+// comandos que modifican la partición fallan hasta que FSCK -checkpoint lo
+// vacía (ver VaciarJournal).
+func (v *Volumen) CapacidadJournal() int64 {
+	if !v.EsEXT3() {
+		return 0
+	}
+	return (v.Super.S_bm_inode_start - v.Super.S_journal_start) / Structs.TamJournal
+}
+
+// LeerJournal retorna las entradas ocupadas del journal en orden
+func (v *Volumen) LeerJournal() ([]Structs.Journal, error) {
+	if !v.EsEXT3() {
+		return nil, fmt.Errorf("la partición no es EXT3, no tiene journaling")
+	}
+
+	var entradas []Structs.Journal
+	for i := int64(0); i < v.CapacidadJournal(); i++ {
+		var entrada Structs.Journal
+		if err := v.leerEstructura(v.Super.S_journal_start+i*Structs.TamJournal, &entrada); err != nil {
+			return nil, fmt.Errorf("error al leer journal: %v", err)
+		}
+		if entrada.J_count == 0 {
+			break
+		}
+		entradas = append(entradas, entrada)
+	}
+	return entradas, nil
+}
+
+// IniciarJournal deja libres todas las entradas del área de journaling. Lo
+// usa MKFS al formatear una partición EXT3.
+func (v *Volumen) IniciarJournal() error {
+	libre := Structs.NewJournal()
+	for i := int64(0); i < v.CapacidadJournal(); i++ {
+		if err := v.escribirEstructura(v.Super.S_journal_start+i*Structs.TamJournal, libre); err != nil {
+			return fmt.Errorf("error al escribir el journal: %v", err)
+		}
+	}
+	return nil
+}
+
+// VaciarJournal libera todas las entradas del journal y retorna cuántas
+// había. Es un punto de control: después de vaciarlo, RECOVERY solo puede
+// reconstruir las operaciones registradas a partir de ese momento.
+func (v *Volumen) VaciarJournal() (int64, error) {
+	entradas, err := v.LeerJournal()
+	if err != nil {
+		return 0, err
+	}
+	libre := Structs.NewJournal()
+	for i := range entradas {
+		if err := v.escribirEstructura(v.Super.S_journal_start+int64(i)*Structs.TamJournal, libre); err != nil {
+			return 0, fmt.Errorf("error al vaciar journal: %v", err)
+		}
+	}
+	fmt.Printf("🔧 DEBUG: Journal vaciado (%d entradas)\n", len(entradas))
+	return int64(len(entradas)), nil
+}
+
+// operacionContinua marca las entradas que continúan el contenido de la
+// operación anterior cuando no cabe en una sola entrada
+const operacionContinua = "continua"
+
+// OperacionJournal es una operación del journal con su contenido completo,
+// reconstruido a partir de sus entradas de continuación
+type OperacionJournal struct {
+	Numero    int64 // J_count de la primera entrada
+	Operacion string
+	Ruta      string
+	Contenido string
+	Fecha     string
+	Uid, Gid  int64 // dueño con el que se reproduce la operación
+	Entradas  int64 // entradas que ocupa en el journal
+}
+
+// LeerOperaciones retorna las operaciones del journal en orden, uniendo el
+// contenido repartido en entradas de continuación
+func (v *Volumen) LeerOperaciones() ([]OperacionJournal, error) {
+	entradas, err := v.LeerJournal()
+	if err != nil {
+		return nil, err
+	}
+
+	var operaciones []OperacionJournal
+	for _, entrada := range entradas {
+		info := entrada.J_content
+		operacion := strings.TrimRight(string(info.I_operation[:]), "\x00")
+		if operacion == operacionContinua {
+			if len(operaciones) == 0 {
+				return nil, fmt.Errorf("la entrada #%d continúa una operación inexistente", entrada.J_count)
+			}
+			op := &operaciones[len(operaciones)-1]
+			op.Contenido += string(info.I_content[:min(int64(len(info.I_content)), info.I_size)])
+			op.Entradas++
+			continue
+		}
+		operaciones = append(operaciones, OperacionJournal{
+			Numero:    entrada.J_count,
+			Operacion: operacion,
+			Ruta:      strings.TrimRight(string(info.I_path[:]), "\x00"),
+			Contenido: string(info.I_content[:min(int64(len(info.I_content)), info.I_size)]),
+			Fecha:     strings.TrimRight(string(info.I_date[:]), "\x00"),
+			Uid:       info.I_uid,
+			Gid:       info.I_gid,
+			Entradas:  1,
+		})
+	}
+	return operaciones, nil
+}
+
+// RegistrarJournal agrega una operación al journal. Los comandos que
+// modifican el sistema de archivos la llaman antes de tocar las estructuras.
+// Se guarda el UID y GID del usuario para que RECOVERY recree los archivos
+// con su dueño original. En EXT2 no hace nada. El contenido que no cabe en una entrada sigue en
+// entradas de continuación; una ruta más larga que I_path se rechaza.
+func (v *Volumen) RegistrarJournal(operacion, ruta, contenido string, uid, gid int64) error {
+	if !v.EsEXT3() {
+		return nil
+	}
+
+	var info Structs.Information
+	if len(ruta) > len(info.I_path) {
+		return fmt.Errorf("la ruta %s excede los %d bytes que admite el journal", ruta, len(info.I_path))
+	}
+
+	entradas, err := v.LeerJournal()
+	if err != nil {
+		return err
+	}
+	siguiente := int64(len(entradas))
+	tamBloque := len(info.I_content)
+	necesarias := int64(max(1, (len(contenido)+tamBloque-1)/tamBloque))
+	if siguiente+necesarias > v.CapacidadJournal() {
+		return fmt.Errorf("el journal no tiene espacio para la operación (%d de %d entradas usadas, se necesitan %d); ejecute FSCK -checkpoint para vaciarlo",
+			siguiente, v.CapacidadJournal(), necesarias)
+	}
+
+	fecha := FechaActual()
+	for i := int64(0); i < necesarias; i++ {
+		entrada := Structs.NewJournal()
+		entrada.J_count = siguiente + i + 1
+		if i == 0 {
+			copy(entrada.J_content.I_operation[:], operacion)
+			copy(entrada.J_content.I_path[:], ruta)
+		} else {
+			copy(entrada.J_content.I_operation[:], operacionContinua)
+		}
+		trozo := contenido[min(len(contenido), int(i)*tamBloque):min(len(contenido), int(i+1)*tamBloque)]
+		copy(entrada.J_content.I_content[:], trozo)
+		entrada.J_content.I_size = int64(len(trozo))
+		entrada.J_content.I_date = fecha
+		entrada.J_content.I_uid = uid
+		entrada.J_content.I_gid = gid
+
+		if err := v.escribirEstructura(v.Super.S_journal_start+(siguiente+i)*Structs.TamJournal, entrada); err != nil {
+			return fmt.Errorf("error al escribir journal: %v", err)
+		}
+	}
+
+	fmt.Printf("🔧 DEBUG: Journal #%d: %s %s (%d bytes en %d entradas)\n", siguiente+1, operacion, ruta, len(contenido), necesarias)
+	return nil
+}
@@ -0,0 +1,166 @@
+package FS
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"godisk-backend/Structs"
+)
+
+// Volumen es el manejador de una partición montada y formateada. Todas las
+// posiciones de inodos y bloques se calculan a partir de su superbloque, de
+// modo que todos los comandos comparten la misma distribución en disco.
+type Volumen struct {
+	Archivo   *os.File
+	Path      string
+	Particion Structs.Particion
+	Super     Structs.SuperBloque
+}
+
+// Abrir abre el disco en modo lectura/escritura y lee el superbloque de la partición
+func Abrir(pathDisco string, particion Structs.Particion) (*Volumen, error) {
+	pathDisco = strings.ReplaceAll(pathDisco, "\"", "")
+	file, err := os.OpenFile(pathDisco, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el disco: %v", err)
+	}
+
+	v := &Volumen{Archivo: file, Path: pathDisco, Particion: particion}
+	if _, err := v.ReadSuperblock(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// Crear abre el disco para formatear la partición con un superbloque nuevo
+// y lo escribe al inicio de la partición. Lo usa MKFS, cuando la partición
+// todavía no tiene un superbloque válido que Abrir pueda leer.
+func Crear(pathDisco string, particion Structs.Particion, super Structs.SuperBloque) (*Volumen, error) {
+	pathDisco = strings.ReplaceAll(pathDisco, "\"", "")
+	file, err := os.OpenFile(pathDisco, os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo abrir el disco: %v", err)
+	}
+
+	v := &Volumen{Archivo: file, Path: pathDisco, Particion: particion, Super: super}
+	if err := v.WriteSuperblock(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return v, nil
+}
+
+// Cerrar sincroniza y cierra el disco
+func (v *Volumen) Cerrar() error {
+	v.Archivo.Sync()
+	return v.Archivo.Close()
+}
+
+// ReadSuperblock lee el superbloque desde el inicio de la partición
+func (v *Volumen) ReadSuperblock() (Structs.SuperBloque, error) {
+	var super Structs.SuperBloque
+	if err := v.leerEstructura(v.Particion.Part_start, &super); err != nil {
+		return super, fmt.Errorf("error al leer superbloque: %v", err)
+	}
+	if super.S_magic != 0xEF53 {
+		return super, fmt.Errorf("la partición no tiene un sistema de archivos válido (¿falta MKFS?)")
+	}
+	if err := super.ValidarVersion(); err != nil {
+		return super, err
+	}
+	v.Super = super
+	return super, nil
+}
+
+// WriteSuperblock persiste el superbloque actual del volumen
+func (v *Volumen) WriteSuperblock() error {
+	if err := v.escribirEstructura(v.Particion.Part_start, v.Super); err != nil {
+		return fmt.Errorf("error al escribir superbloque: %v", err)
+	}
+	return nil
+}
+
+// posicionInodo calcula el offset del inodo n
+func (v *Volumen) posicionInodo(n int64) (int64, error) {
+	if n < 0 || n >= v.Super.S_inodes_count {
+		return 0, fmt.Errorf("inodo fuera de rango: %d", n)
+	}
+	return v.Super.S_inode_start + n*v.Super.S_inode_size, nil
+}
+
+// posicionBloque calcula el offset del bloque n
+func (v *Volumen) posicionBloque(n int64) (int64, error) {
+	if n < 0 || n >= v.Super.S_blocks_count {
+		return 0, fmt.Errorf("bloque fuera de rango: %d", n)
+	}
+	return v.Super.S_block_start + n*v.Super.S_block_size, nil
+}
+
+// ReadInode lee el inodo n
+func (v *Volumen) ReadInode(n int64) (Structs.Inodos, error) {
+	var inodo Structs.Inodos
+	pos, err := v.posicionInodo(n)
+	if err != nil {
+		return inodo, err
+	}
+	if err := v.leerEstructura(pos, &inodo); err != nil {
+		return inodo, fmt.Errorf("error al leer inodo %d: %v", n, err)
+	}
+	return inodo, nil
+}
+
+// WriteInode escribe el inodo n
+func (v *Volumen) WriteInode(n int64, inodo Structs.Inodos) error {
+	pos, err := v.posicionInodo(n)
+	if err != nil {
+		return err
+	}
+	if err := v.escribirEstructura(pos, inodo); err != nil {
+		return fmt.Errorf("error al escribir inodo %d: %v", n, err)
+	}
+	return nil
+}
+
+// ReadBlock lee el bloque n en destino (BloquesCarpetas, BloquesArchivos o BloquesApuntadores)
+func (v *Volumen) ReadBlock(n int64, destino interface{}) error {
+	pos, err := v.posicionBloque(n)
+	if err != nil {
+		return err
+	}
+	if err := v.leerEstructura(pos, destino); err != nil {
+		return fmt.Errorf("error al leer bloque %d: %v", n, err)
+	}
+	return nil
+}
+
+// WriteBlock escribe origen en el bloque n
+func (v *Volumen) WriteBlock(n int64, origen interface{}) error {
+	pos, err := v.posicionBloque(n)
+	if err != nil {
+		return err
+	}
+	if err := v.escribirEstructura(pos, origen); err != nil {
+		return fmt.Errorf("error al escribir bloque %d: %v", n, err)
+	}
+	return nil
+}
+
+// leerEstructura decodifica una estructura desde la posición indicada
+func (v *Volumen) leerEstructura(pos int64, destino interface{}) error {
+	return Structs.LeerEstructura(v.Archivo, pos, destino)
+}
+
+// escribirEstructura codifica una estructura en la posición indicada
+func (v *Volumen) escribirEstructura(pos int64, origen interface{}) error {
+	return Structs.EscribirEstructura(v.Archivo, pos, origen)
+}
+
+// FechaActual retorna la fecha actual en el formato de los inodos
+func FechaActual() [16]byte {
+	var fecha [16]byte
+	copy(fecha[:], time.Now().Format("2006-01-02 15:04"))
+	return fecha
+}
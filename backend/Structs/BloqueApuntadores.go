@@ -1,5 +1,14 @@
 package Structs
 
+// B_pointers usa int32 para que el bloque ocupe 64 bytes como los demás bloques
 type BloquesApuntadores struct {
-	B_pointers [16]int
+	B_pointers [16]int32
+}
+
+func NewBloquesApuntadores() BloquesApuntadores {
+	var ap BloquesApuntadores
+	for i := 0; i < len(ap.B_pointers); i++ {
+		ap.B_pointers[i] = -1
+	}
+	return ap
 }
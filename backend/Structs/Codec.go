@@ -0,0 +1,119 @@
+package Structs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Todas las estructuras que viven en el disco se serializan campo por campo,
+// en el orden en que están declaradas, sin relleno y con un único orden de
+// bytes. Cualquier cambio en los campos de MBR, EBR, Particion, SuperBloque,
+// Inodos, Journal o los bloques cambia la distribución y obliga a subir VersionFormato.
+
+// OrdenBytes es el único orden de bytes usado en disco
+var OrdenBytes = binary.BigEndian
+
+// VersionFormato se guarda en el MBR y en el superbloque
+const VersionFormato int64 = 3
+
+// Tamaños serializados de cada estructura (en bytes)
+const (
+	// status(1) + type(1) + fit(1) + start(8) + size(8) + name(16)
+	TamParticion = 1 + 1 + 1 + 8 + 8 + 16
+	// tamano(8) + fecha(16) + signature(8) + fit(2) + 4 particiones + version(8)
+	TamMBR = 8 + 16 + 8 + 2 + 4*TamParticion + 8
+	// status(1) + fit(1) + start(8) + size(8) + next(8) + name(16)
+	TamEBR = 1 + 1 + 8 + 8 + 8 + 16
+	// 16 campos int64 + 2 fechas(16) + version(8)
+	TamSuperBloque = 16*8 + 2*16 + 8
+	// uid, gid, size(8 c/u) + 3 fechas(16) + 16 apuntadores(8) + type(8) + perm(8)
+	TamInodo = 3*8 + 3*16 + 16*8 + 8 + 8
+	// name(12) + inodo(8)
+	TamContent = 12 + 8
+	// 4 entradas; es el bloque más grande y define S_block_size
+	TamBloqueCarpetas = 4 * TamContent
+	// 64 bytes de contenido
+	TamBloqueArchivos = 64
+	// 16 apuntadores int32
+	TamBloqueApuntadores = 16 * 4
+	// count(8) + operation(10) + path(32) + content(64) + date(16) + size, uid, gid(8 c/u)
+	TamJournal = 8 + 10 + 32 + 64 + 16 + 3*8
+)
+
+// init verifica que las estructuras coincidan con la distribución declarada
+func init() {
+	esperados := []struct {
+		nombre string
+		valor  interface{}
+		tam    int
+	}{
+		{"Particion", Particion{}, TamParticion},
+		{"MBR", MBR{}, TamMBR},
+		{"EBR", EBR{}, TamEBR},
+		{"SuperBloque", SuperBloque{}, TamSuperBloque},
+		{"Inodos", Inodos{}, TamInodo},
+		{"Content", Content{}, TamContent},
+		{"BloquesCarpetas", BloquesCarpetas{}, TamBloqueCarpetas},
+		{"BloquesArchivos", BloquesArchivos{}, TamBloqueArchivos},
+		{"BloquesApuntadores", BloquesApuntadores{}, TamBloqueApuntadores},
+		{"Journal", Journal{}, TamJournal},
+	}
+	for _, e := range esperados {
+		if real := binary.Size(e.valor); real != e.tam {
+			panic(fmt.Sprintf("distribución de %s cambió (%d bytes, se esperaban %d): actualice el codec y VersionFormato", e.nombre, real, e.tam))
+		}
+	}
+}
+
+// Tamaño retorna el tamaño serializado de una estructura
+func Tamaño(v interface{}) int64 {
+	return int64(binary.Size(v))
+}
+
+// Codificar serializa una estructura
+func Codificar(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := binary.Write(&buffer, OrdenBytes, v); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decodificar deserializa datos en la estructura apuntada por v
+func Decodificar(datos []byte, v interface{}) error {
+	return binary.Read(bytes.NewReader(datos), OrdenBytes, v)
+}
+
+// LeerEstructura lee una estructura en la posición indicada
+func LeerEstructura(r io.ReaderAt, pos int64, v interface{}) error {
+	seccion := io.NewSectionReader(r, pos, Tamaño(v))
+	return binary.Read(seccion, OrdenBytes, v)
+}
+
+// EscribirEstructura escribe una estructura en la posición indicada
+func EscribirEstructura(w io.WriterAt, pos int64, v interface{}) error {
+	datos, err := Codificar(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.WriteAt(datos, pos)
+	return err
+}
+
+// ValidarVersion rechaza discos creados con otra distribución
+func (m MBR) ValidarVersion() error {
+	if m.Mbr_version != VersionFormato {
+		return fmt.Errorf("el disco usa una versión de formato incompatible (%d, se esperaba %d); vuelva a crearlo con MKDISK", m.Mbr_version, VersionFormato)
+	}
+	return nil
+}
+
+// ValidarVersion rechaza sistemas de archivos creados con otra distribución
+func (s SuperBloque) ValidarVersion() error {
+	if s.S_version != VersionFormato {
+		return fmt.Errorf("el sistema de archivos usa una versión de formato incompatible (%d, se esperaba %d); vuelva a formatear con MKFS", s.S_version, VersionFormato)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package Structs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// estructurasPrueba retorna una instancia con datos de cada estructura del
+// disco y el tamaño serializado que le corresponde
+func estructurasPrueba() []struct {
+	nombre string
+	valor  interface{}
+	tam    int
+} {
+	particion := NewParticion()
+	particion.Part_status, particion.Part_type, particion.Part_fit = '1', 'E', 'B'
+	particion.Part_start, particion.Part_size = 1234, 5678
+	copy(particion.Part_name[:], "Part1")
+
+	mbr := NewMBR()
+	mbr.Mbr_tamano, mbr.Mbr_dsk_signature = 5*1024*1024, 42
+	copy(mbr.Mbr_fecha_creacion[:], "2024-01-02 03:04")
+	mbr.Dsk_fit = [2]byte{'W', 'F'}
+	mbr.Mbr_partition_2 = particion
+
+	ebr := NewEBR()
+	ebr.Part_status, ebr.Part_fit = '1', 'F'
+	ebr.Part_start, ebr.Part_size, ebr.Part_next = 100, 200, 300
+	copy(ebr.Part_name[:], "Logica")
+
+	super := NewSuperBloque()
+	super.S_filesystem_type, super.S_inodes_count, super.S_blocks_count = 3, 10, 30
+	super.S_free_blocks_count, super.S_free_inodes_count = -7, 8
+	super.S_bm_block_start, super.S_journal_start = 1<<40, 99
+
+	inodo := NewInodos()
+	inodo.I_uid, inodo.I_gid, inodo.I_size, inodo.I_type, inodo.I_perm = 1, 2, 300, 1, 664
+	inodo.I_block[0], inodo.I_block[13] = 7, 8
+
+	content := NewContent()
+	copy(content.B_name[:], "users.txt")
+	content.B_inodo = 1
+
+	carpetas := NewBloquesCarpetas()
+	carpetas.B_content[3] = content
+
+	var archivos BloquesArchivos
+	copy(archivos.B_content[:], "1,G,root\n1,U,root,root,123\n")
+
+	apuntadores := NewBloquesApuntadores()
+	apuntadores.B_pointers[0], apuntadores.B_pointers[15] = 1, -2
+
+	journal := NewJournal()
+	journal.J_count = 3
+	copy(journal.J_content.I_operation[:], "mkfile")
+	copy(journal.J_content.I_path[:], "/home/a.txt")
+	journal.J_content.I_size, journal.J_content.I_uid, journal.J_content.I_gid = 4, 2, 3
+
+	return []struct {
+		nombre string
+		valor  interface{}
+		tam    int
+	}{
+		{"Particion", particion, TamParticion},
+		{"MBR", mbr, TamMBR},
+		{"EBR", ebr, TamEBR},
+		{"SuperBloque", super, TamSuperBloque},
+		{"Inodos", inodo, TamInodo},
+		{"Content", content, TamContent},
+		{"BloquesCarpetas", carpetas, TamBloqueCarpetas},
+		{"BloquesArchivos", archivos, TamBloqueArchivos},
+		{"BloquesApuntadores", apuntadores, TamBloqueApuntadores},
+		{"Journal", journal, TamJournal},
+	}
+}
+
+func TestCodificarDecodificar(t *testing.T) {
+	for _, e := range estructurasPrueba() {
+		t.Run(e.nombre, func(t *testing.T) {
+			datos, err := Codificar(e.valor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(datos) != e.tam || Tamaño(e.valor) != int64(e.tam) {
+				t.Fatalf("%s ocupa %d bytes (Tamaño %d), se esperaban %d", e.nombre, len(datos), Tamaño(e.valor), e.tam)
+			}
+
+			copia := reflect.New(reflect.TypeOf(e.valor))
+			if err := Decodificar(datos, copia.Interface()); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(copia.Elem().Interface(), e.valor) {
+				t.Errorf("%s no se recupera igual:\n%+v\n%+v", e.nombre, copia.Elem().Interface(), e.valor)
+			}
+
+			if err := Decodificar(datos[:len(datos)-1], copia.Interface()); err == nil {
+				t.Errorf("%s se decodificó con un byte de menos", e.nombre)
+			}
+		})
+	}
+}
+
+func TestLeerEscribirEstructura(t *testing.T) {
+	archivo, err := os.Create(filepath.Join(t.TempDir(), "disco.mia"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archivo.Close()
+
+	// Cada estructura en una posición distinta, una detrás de otra
+	pos := int64(3)
+	posiciones := map[string]int64{}
+	for _, e := range estructurasPrueba() {
+		if err := EscribirEstructura(archivo, pos, e.valor); err != nil {
+			t.Fatal(err)
+		}
+		posiciones[e.nombre] = pos
+		pos += int64(e.tam)
+	}
+
+	for _, e := range estructurasPrueba() {
+		leida := reflect.New(reflect.TypeOf(e.valor))
+		if err := LeerEstructura(archivo, posiciones[e.nombre], leida.Interface()); err != nil {
+			t.Fatalf("%s: %v", e.nombre, err)
+		}
+		if !reflect.DeepEqual(leida.Elem().Interface(), e.valor) {
+			t.Errorf("%s leído en %d no coincide con el escrito", e.nombre, posiciones[e.nombre])
+		}
+	}
+}
+
+func TestOrdenBytes(t *testing.T) {
+	ebr := NewEBR()
+	ebr.Part_start = 0x0102030405060708
+
+	datos, err := Codificar(ebr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Part_start va después de status y fit, en big endian
+	esperado := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	if !reflect.DeepEqual(datos[2:10], esperado) {
+		t.Errorf("Part_start se serializó como % x, se esperaba % x", datos[2:10], esperado)
+	}
+}
+
+func TestValidarVersion(t *testing.T) {
+	casos := []struct {
+		nombre  string
+		version int64
+		valido  bool
+	}{
+		{"actual", VersionFormato, true},
+		{"anterior", VersionFormato - 1, false},
+		{"sin versión", 0, false},
+	}
+	for _, c := range casos {
+		t.Run(c.nombre, func(t *testing.T) {
+			mbr := NewMBR()
+			mbr.Mbr_version = c.version
+			super := NewSuperBloque()
+			super.S_version = c.version
+			if err := mbr.ValidarVersion(); (err == nil) != c.valido {
+				t.Errorf("MBR versión %d: %v", c.version, err)
+			}
+			if err := super.ValidarVersion(); (err == nil) != c.valido {
+				t.Errorf("SuperBloque versión %d: %v", c.version, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package Structs
+
+// Information describe una operación registrada en el journal (EXT3)
+type Information struct {
+	I_operation [10]byte
+	I_path      [32]byte
+	I_content   [64]byte
+	I_date      [16]byte
+	I_size      int64 // bytes usados de I_content
+	I_uid       int64 // usuario que ejecutó la operación
+	I_gid       int64 // grupo del usuario que ejecutó la operación
+}
+
+// Journal es una entrada del área de journaling. J_count en 0 indica
+// una entrada libre; las ocupadas se numeran desde 1. El contenido que no
+// cabe en I_content sigue en entradas con operación "continua".
+type Journal struct {
+	J_count   int64
+	J_content Information
+}
+
+func NewJournal() Journal {
+	var jr Journal
+	jr.J_count = 0
+	return jr
+}
@@ -9,6 +9,7 @@ type MBR struct {
 	Mbr_partition_2    Particion
 	Mbr_partition_3    Particion
 	Mbr_partition_4    Particion
+	Mbr_version        int64 // VersionFormato con la que se creó el disco
 }
 
 func NewMBR() MBR {
@@ -18,5 +19,6 @@ func NewMBR() MBR {
 	mb.Mbr_partition_2 = NewParticion()
 	mb.Mbr_partition_3 = NewParticion()
 	mb.Mbr_partition_4 = NewParticion()
+	mb.Mbr_version = VersionFormato
 	return mb
 }
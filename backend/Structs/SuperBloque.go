@@ -1,7 +1,5 @@
 package Structs
 
-import "unsafe"
-
 type SuperBloque struct {
 	S_filesystem_type   int64
 	S_inodes_count      int64
@@ -21,16 +19,18 @@ type SuperBloque struct {
 	S_inode_start       int64
 	S_block_start       int64
 	S_journal_start     int64 // en caso de ext3
-
+	S_version           int64 // VersionFormato con la que se formateó
 }
 
 func NewSuperBloque() SuperBloque {
 	var spr SuperBloque
 	spr.S_magic = 0xEF53
-	spr.S_inode_size = int64(unsafe.Sizeof(Inodos{}))
-	spr.S_block_size = int64(unsafe.Sizeof(BloquesCarpetas{}))
+	spr.S_inode_size = TamInodo
+	// Todos los bloques ocupan lo mismo que el más grande (el de carpetas)
+	spr.S_block_size = TamBloqueCarpetas
 	spr.S_firts_ino = 0
 	spr.S_first_blo = 0
 	spr.S_journal_start = 0
+	spr.S_version = VersionFormato
 	return spr
 }
@@ -47,6 +47,9 @@ func ParseCommand(command string) map[string]string {
 	return params
 }
 
+// SepararTokens divide la línea de parámetros en tokens "clave=valor". Los
+// parámetros sin valor (-p, -r, -repair...) se retornan como "-clave"; las
+// comillas delimitan valores con espacios.
 func SepararTokens(texto string) []string {
 	var tokens []string
 	if texto == "" {
@@ -66,6 +69,16 @@ func SepararTokens(texto string) []string {
 				if c == "=" {
 					estado = 2
 				} else if c == " " {
+					// Un espacio seguido de algo que no es "=" cierra un flag sin valor
+					siguiente := strings.TrimLeft(texto[i:], " ")
+					if strings.HasPrefix(siguiente, "=") {
+						continue
+					}
+					if token != "" {
+						tokens = append(tokens, "-"+token)
+						token = ""
+					}
+					estado = 0
 					continue
 				}
 			} else if estado == 2 {
@@ -233,21 +233,6 @@ func executeCommand(command string) string {
 	// DEBUG: mostrar commandLine y tokens para diagnosticar parseo de flags
 	fmt.Printf("🔧 DEBUG: commandLine='%s' -> tokens=%v\n", commandLine, tokens)
 
-	// Si el comando original contiene "-p" pero tokens no, añadirlo (evita que se pierda el flag)
-	if strings.Contains(command, " -p") || strings.HasPrefix(command, "-p") || strings.Contains(command, "-p ") {
-		hasP := false
-		for _, t := range tokens {
-			if t == "-p" || t == "p" {
-				hasP = true
-				break
-			}
-		}
-		if !hasP {
-			tokens = append(tokens, "-p")
-			fmt.Printf("🔧 DEBUG: auto-added token '-p' -> tokens=%v\n", tokens)
-		}
-	}
-
 	switch cmd {
 	case "MKDISK":
 		return Comandos.ValidarDatosMKDISK(tokens)
@@ -283,6 +268,22 @@ func executeCommand(command string) string {
 		return Comandos.ValidarDatosMKFILE(tokens)
 	case "MKDIR":
 		return Comandos.ValidarDatosMKDIR(tokens)
+	case "LOSS":
+		return Comandos.ValidarDatosLOSS(tokens)
+	case "RECOVERY":
+		return Comandos.ValidarDatosRECOVERY(tokens)
+	case "FSCK":
+		return Comandos.ValidarDatosFSCK(tokens)
+	case "REMOVE":
+		return Comandos.ValidarDatosREMOVE(tokens)
+	case "EDIT":
+		return Comandos.ValidarDatosEDIT(tokens)
+	case "RENAME":
+		return Comandos.ValidarDatosRENAME(tokens)
+	case "COPY":
+		return Comandos.ValidarDatosCOPY(tokens)
+	case "MOVE":
+		return Comandos.ValidarDatosMOVE(tokens)
 	default:
 		return fmt.Sprintf("⚠️ Comando no reconocido: %s", cmd)
 	}